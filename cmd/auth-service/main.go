@@ -1,88 +1,342 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
 	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
 	"github.com/Koshsky/subs-service/auth-service/internal/server"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-// setupServices initializes all services and returns them
-func setupServices(cfg *config.Config) (*services.AuthService, *server.AuthServer, error) {
+// connectBroker connects to RabbitMQ
+// An unavailable broker is an error when RABBITMQ_REQUIRED is set, otherwise events are buffered in the outbox
+// until the broker is reachable, or the service runs without events when no outbox is configured
+func connectBroker(cfg config.RabbitMQConfig) (messaging.IMessageBroker, error) {
+	rabbitmqService, err := messaging.NewRabbitMQAdapter(cfg)
+	if err == nil {
+		metrics.BrokerConnected.Set(1)
+		return rabbitmqService, nil
+	}
+
+	metrics.BrokerConnected.Set(0)
+	if cfg.Required {
+		return nil, fmt.Errorf("RabbitMQ is required but unavailable: %w", err)
+	}
+	slog.Warn("Failed to initialize RabbitMQ service", slog.String("error", err.Error()))
+	if cfg.OutboxPath != "" {
+		bufferingService, err := messaging.NewBufferingRabbitMQAdapter(cfg)
+		if err == nil {
+			slog.Warn("Events will be buffered in the outbox until RabbitMQ is reachable")
+			return bufferingService, nil
+		}
+		slog.Warn("Failed to buffer events in the outbox", slog.String("error", err.Error()))
+	}
+	slog.Warn("Auth service will continue without event publishing")
+	return nil, nil
+}
+
+// setupServices initializes all services and returns them along with the primary database,
+// used by the readiness probe, and a cleanup function that releases broker and database connections
+// Cleanup drains unconfirmed events until its context is done
+func setupServices(cfg *config.Config) (*services.AuthService, *server.AuthServer, server.Pinger, func(context.Context), error) {
 	// Initialize RabbitMQ service
-	rabbitmqService, err := messaging.NewRabbitMQAdapter(cfg.RabbitMQ)
+	rabbitmqService, err := connectBroker(cfg.RabbitMQ)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize RabbitMQ service: %v", err)
-		log.Printf("Auth service will continue without event publishing")
-		rabbitmqService = nil
+		return nil, nil, nil, nil, err
 	}
 
 	// Initialize database and repositories
 	gormAdapter, err := repositories.NewGormAdapter(&cfg.Database)
 	if err != nil {
-		return nil, nil, err
+		if rabbitmqService != nil {
+			rabbitmqService.Close()
+		}
+		return nil, nil, nil, nil, err
 	}
+	databases := []repositories.IDatabase{gormAdapter}
+	warmupPool(gormAdapter, cfg.Database.PoolWarmup, cfg.ReadinessTimeout)
+	metrics.Registry.MustRegister(metrics.NewDBPoolCollector("primary", gormAdapter.Stats))
 	userRepo := repositories.NewUserRepository(gormAdapter)
+	if cfg.Database.ReadReplicaHost != "" {
+		replicaAdapter, err := repositories.NewGormReadReplicaAdapter(&cfg.Database)
+		if err != nil {
+			slog.Warn("Failed to connect to read replica, read queries will use the primary database", slog.String("error", err.Error()))
+		} else {
+			databases = append(databases, replicaAdapter)
+			metrics.Registry.MustRegister(metrics.NewDBPoolCollector("replica", replicaAdapter.Stats))
+			userRepo = repositories.NewUserRepositoryWithReplica(gormAdapter, replicaAdapter)
+		}
+	}
 	authService := services.NewAuthService(userRepo, rabbitmqService, cfg)
 	authServer := server.NewAuthServer(authService)
 
-	return authService, authServer, nil
+	cleanup := func(ctx context.Context) {
+		if rabbitmqService != nil {
+			if err := rabbitmqService.Drain(ctx); err != nil {
+				slog.Warn("Failed to drain pending events", slog.String("error", err.Error()))
+			}
+			rabbitmqService.Close()
+		}
+		for _, db := range databases {
+			if err := db.Close(); err != nil {
+				slog.Warn("Failed to close database connection", slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	return authService, authServer, gormAdapter, cleanup, nil
+}
+
+// warmupPool opens n database connections up front, bounded by timeout
+// A failed warmup is not fatal, the pool still opens connections on demand
+func warmupPool(db repositories.IDatabase, n int, timeout time.Duration) {
+	if n <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := db.Warmup(ctx, n); err != nil {
+		slog.Warn("Failed to warm up database pool", slog.String("error", err.Error()))
+		return
+	}
+	slog.Info("Database pool warmed up", slog.Int("connections", n))
+}
+
+// bootstrapAdmin seeds the configured admin user on a fresh deployment
+// It runs against the migrated schema and does nothing once any user exists
+func bootstrapAdmin(ctx context.Context, authService *services.AuthService, cfg config.BootstrapAdminConfig) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	created, err := authService.BootstrapAdmin(ctx, cfg.Email, cfg.Password)
+	if err != nil {
+		return err
+	}
+	if created {
+		slog.Info("Bootstrap admin user created", slog.String("email", utils.MaskEmail(cfg.Email)))
+	} else {
+		slog.Info("Users already exist, skipping bootstrap admin user")
+	}
+	return nil
 }
 
 // createGRPCServer creates and configures the gRPC server
-func createGRPCServer(cfg *config.Config) (*grpc.Server, error) {
-	var grpcServer *grpc.Server
+// authService backs the auth interceptor and maintenance the maintenance guard, nil leaves either out of the chain
+func createGRPCServer(cfg *config.Config, authService services.IAuthService, maintenance *server.Maintenance) (*grpc.Server, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(server.BuildInterceptors(cfg, authService, maintenance)...),
+		grpc.ChainStreamInterceptor(server.BuildStreamInterceptors(cfg, authService, maintenance)...),
+	}
 
 	if cfg.EnableTLS {
-		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertFile, cfg.TLSKeyFile)
+		tlsConfig, err := server.BuildTLSConfig(cfg)
 		if err != nil {
 			return nil, err
 		}
-		grpcServer = grpc.NewServer(grpc.Creds(creds))
-	} else {
-		grpcServer = grpc.NewServer()
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	return grpc.NewServer(opts...), nil
+}
+
+// newMetricsServer creates the HTTP server exposing Prometheus metrics and the readiness probe
+// Browser requests are restricted to the configured allowed origins, the probe is exempt
+func newMetricsServer(cfg *config.Config, db server.Pinger) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle(server.ReadinessPath, server.ReadinessHandler(db, cfg.ReadinessTimeout))
+
+	return &http.Server{
+		Addr:              ":" + cfg.MetricsPort,
+		Handler:           server.AllowedOriginsMiddleware(cfg.AllowedOrigins, []string{server.ReadinessPath})(mux),
+		ReadHeaderTimeout: 5 * time.Second,
 	}
+}
+
+// startMetricsServer starts the metrics HTTP server in the background
+func startMetricsServer(metricsServer *http.Server) {
+	go func() {
+		slog.Info("Metrics server starting", slog.String("addr", metricsServer.Addr))
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("Metrics server stopped", slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// watchMaintenanceSignal toggles maintenance mode on every SIGUSR1 until ctx is done
+func watchMaintenanceSignal(ctx context.Context, maintenance *server.Maintenance) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-sigCh:
+				slog.Info("Maintenance mode toggled", slog.Bool("enabled", maintenance.Toggle()))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
 
-	return grpcServer, nil
+// grpcStopper is the part of *grpc.Server used during shutdown
+type grpcStopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// stopGRPCServer drains in-flight RPCs and forces the server down if ctx is done first
+func stopGRPCServer(ctx context.Context, grpcServer grpcStopper) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("Graceful stop did not finish in time, forcing gRPC server stop")
+		grpcServer.Stop()
+		<-stopped
+	}
+}
+
+// waitForShutdown blocks until SIGINT or SIGTERM is received or the server stops on its own
+func waitForShutdown(serveErr <-chan error) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	select {
+	case sig := <-sigCh:
+		slog.Info("Shutting down", slog.String("signal", sig.String()))
+	case err := <-serveErr:
+		slog.Warn("gRPC server stopped", slog.String("error", err.Error()))
+	}
 }
 
-// startServer starts the gRPC server
-func startServer(grpcServer *grpc.Server, authServer *server.AuthServer, port string) error {
+// startServer registers the auth and health services and starts the gRPC server
+func startServer(grpcServer *grpc.Server, authServer *server.AuthServer, healthServer *health.Server, port string) error {
 	authpb.RegisterAuthServiceServer(grpcServer, authServer)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
 
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("Auth service starting on port %s", port)
+	slog.Info("Auth service starting", slog.String("port", port))
 	return grpcServer.Serve(lis)
 }
 
+// runtimeMetadata describes the Go runtime of the process, logged at startup to debug environment issues
+func runtimeMetadata() map[string]any {
+	return map[string]any{
+		"go_version": runtime.Version(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"num_cpu":    runtime.NumCPU(),
+	}
+}
+
+// logStartupSummary logs the service version and runtime metadata once startup has finished
+func logStartupSummary(cfg *config.Config) {
+	metadata := runtimeMetadata()
+	attrs := []any{
+		slog.String("version", server.BuildVersion()),
+		slog.String("environment", cfg.Environment),
+		slog.String("port", cfg.Port),
+	}
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		attrs = append(attrs, slog.Any(key, metadata[key]))
+	}
+	slog.Info("Startup complete", attrs...)
+}
+
+// fatal logs err through the configured handler and exits, deferred calls do not run
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.String("error", err.Error()))
+	os.Exit(1)
+}
+
 func main() {
 	cfg := config.LoadConfig()
+	logging.InitLogging(cfg.Log)
+	cfg.LogSources()
 
 	// Setup services
-	_, authServer, err := setupServices(cfg)
+	authService, authServer, primaryDB, cleanup, err := setupServices(cfg)
 	if err != nil {
-		log.Fatalf("Failed to setup services: %v", err)
+		fatal("Failed to setup services", err)
+	}
+
+	// Start metrics server, the readiness probe and the gRPC health service report not ready until startup has finished
+	healthServer := server.NewHealthServer()
+	startupGate := server.NewStartupGate(primaryDB)
+	startupGate.AttachHealth(healthServer)
+	metricsServer := newMetricsServer(cfg, startupGate)
+	startMetricsServer(metricsServer)
+
+	if err := bootstrapAdmin(context.Background(), authService, cfg.BootstrapAdmin); err != nil {
+		fatal("Failed to bootstrap admin user", err)
 	}
 
 	// Create gRPC server
-	grpcServer, err := createGRPCServer(cfg)
+	maintenance := server.NewMaintenance(cfg.MaintenanceMode, cfg.MaintenanceMessage)
+	grpcServer, err := createGRPCServer(cfg, authService, maintenance)
 	if err != nil {
-		log.Fatalf("Failed to create gRPC server: %v", err)
+		fatal("Failed to create gRPC server", err)
 	}
+	maintenanceCtx, stopMaintenanceWatch := context.WithCancel(context.Background())
+	defer stopMaintenanceWatch()
+	watchMaintenanceSignal(maintenanceCtx, maintenance)
+
+	pingCtx, cancelPing := context.WithTimeout(context.Background(), cfg.ReadinessTimeout)
+	err = startupGate.Open(pingCtx)
+	cancelPing()
+	if err != nil {
+		fatal("Database is not reachable after startup", err)
+	}
+	logStartupSummary(cfg)
+
+	// Start server and wait for a shutdown signal
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- startServer(grpcServer, authServer, healthServer, cfg.Port)
+	}()
+	waitForShutdown(serveErr)
 
-	// Start server
-	if err := startServer(grpcServer, authServer, cfg.Port); err != nil {
-		log.Printf("gRPC server stopped: %v", err)
+	// The whole shutdown sequence shares one deadline so a stuck step cannot block forever
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	healthServer.Shutdown()
+	stopGRPCServer(ctx, grpcServer)
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		slog.Warn("Metrics server shutdown failed", slog.String("error", err.Error()))
 	}
+	cleanup(ctx)
 }
@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,7 +25,7 @@ func TestCreateGRPCServer_WithoutTLS_Unit(t *testing.T) {
 	}
 
 	// Act
-	grpcServer, err := createGRPCServer(cfg)
+	grpcServer, err := createGRPCServer(cfg, nil, nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -32,7 +41,7 @@ func TestCreateGRPCServer_WithTLS_InvalidFiles_Unit(t *testing.T) {
 	}
 
 	// Act
-	grpcServer, err := createGRPCServer(cfg)
+	grpcServer, err := createGRPCServer(cfg, nil, nil)
 
 	// Assert
 	require.Error(t, err)
@@ -40,6 +49,56 @@ func TestCreateGRPCServer_WithTLS_InvalidFiles_Unit(t *testing.T) {
 	assert.Contains(t, err.Error(), "open nonexistent.crt")
 }
 
+func TestNewMetricsServer_ServesMetrics(t *testing.T) {
+	// Arrange
+	metricsServer := newMetricsServer(&config.Config{MetricsPort: "9090"}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	metricsServer.Handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, ":9090", metricsServer.Addr)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "auth_service_db_slow_queries_total")
+}
+
+func TestNewMetricsServer_RejectsDisallowedOrigin(t *testing.T) {
+	// Arrange
+	metricsServer := newMetricsServer(&config.Config{
+		MetricsPort:    "9090",
+		AllowedOrigins: []string{"https://admin.example.com"},
+	}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	rec := httptest.NewRecorder()
+
+	// Act
+	metricsServer.Handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestNewMetricsServer_ServesReadinessToAnyOrigin(t *testing.T) {
+	// Arrange
+	metricsServer := newMetricsServer(&config.Config{
+		MetricsPort:      "9090",
+		AllowedOrigins:   []string{"https://admin.example.com"},
+		ReadinessTimeout: time.Second,
+	}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	rec := httptest.NewRecorder()
+
+	// Act
+	metricsServer.Handler.ServeHTTP(rec, req)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
 func TestStartServer_InvalidPort(t *testing.T) {
 	// This test verifies that invalid ports are properly handled
 	// We'll test the net.Listen function directly since that's what fails with invalid ports
@@ -160,7 +219,7 @@ func TestGRPCServerConfiguration(t *testing.T) {
 		}
 
 		// Act
-		server, err := createGRPCServer(cfg)
+		server, err := createGRPCServer(cfg, nil, nil)
 
 		// Assert
 		require.NoError(t, err)
@@ -182,7 +241,7 @@ func TestGRPCServerConfiguration(t *testing.T) {
 		}
 
 		// Act
-		server, err := createGRPCServer(cfg)
+		server, err := createGRPCServer(cfg, nil, nil)
 
 		// Assert
 		require.Error(t, err)
@@ -190,3 +249,139 @@ func TestGRPCServerConfiguration(t *testing.T) {
 		assert.Contains(t, err.Error(), "missing.crt")
 	})
 }
+
+func TestBootstrapAdmin_DisabledIsNoop(t *testing.T) {
+	// Arrange
+	cfg := config.BootstrapAdminConfig{}
+
+	// Act
+	err := bootstrapAdmin(context.Background(), nil, cfg)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+func TestConnectBroker_Unavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+	}{
+		{name: "Required broker fails startup", required: true},
+		{name: "Optional broker degrades to no events", required: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cfg := config.RabbitMQConfig{URL: "invalid://url", Exchange: "test_exchange", Required: tt.required}
+
+			// Act
+			broker, err := connectBroker(cfg)
+
+			// Assert
+			assert.Nil(t, broker)
+			if tt.required {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "RabbitMQ is required but unavailable")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConnectBroker_UnavailableWithOutboxBuffersEvents(t *testing.T) {
+	// Arrange
+	cfg := config.RabbitMQConfig{
+		URL:        "invalid://url",
+		Exchange:   "test_exchange",
+		OutboxPath: filepath.Join(t.TempDir(), "outbox.jsonl"),
+	}
+
+	// Act
+	broker, err := connectBroker(cfg)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, broker)
+	defer broker.Close()
+	require.NoError(t, broker.PublishUserDeleted(context.Background(), &models.User{ID: uuid.New()}))
+	outbox, err := messaging.NewOutbox(cfg.OutboxPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, outbox.Len())
+}
+
+func TestSetupServices_RequiredBrokerUnavailable(t *testing.T) {
+	// Arrange
+	cfg := &config.Config{
+		RabbitMQ: config.RabbitMQConfig{URL: "invalid://url", Exchange: "test_exchange", Required: true},
+	}
+
+	// Act
+	authService, authServer, db, cleanup, err := setupServices(cfg)
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RabbitMQ is required but unavailable")
+	assert.Nil(t, authService)
+	assert.Nil(t, authServer)
+	assert.Nil(t, db)
+	assert.Nil(t, cleanup)
+}
+
+// slowDrainServer is a grpcStopper whose graceful stop only returns once Stop is called
+type slowDrainServer struct {
+	forced chan struct{}
+}
+
+func (s *slowDrainServer) GracefulStop() {
+	<-s.forced
+}
+
+func (s *slowDrainServer) Stop() {
+	close(s.forced)
+}
+
+func TestStopGRPCServer_ForcesStopAfterTimeout(t *testing.T) {
+	// Arrange
+	srv := &slowDrainServer{forced: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+
+	// Act
+	stopGRPCServer(ctx, srv)
+
+	// Assert
+	assert.Less(t, time.Since(start), time.Second)
+	select {
+	case <-srv.forced:
+	default:
+		t.Fatal("expected the server to be stopped forcibly")
+	}
+}
+
+func TestStopGRPCServer_GracefulWithinTimeout(t *testing.T) {
+	// Arrange
+	grpcServer, err := createGRPCServer(&config.Config{}, nil, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	stopGRPCServer(ctx, grpcServer)
+
+	// Assert
+	assert.NoError(t, ctx.Err())
+}
+
+func TestRuntimeMetadata(t *testing.T) {
+	// Act
+	metadata := runtimeMetadata()
+
+	// Assert
+	assert.Equal(t, runtime.Version(), metadata["go_version"])
+	assert.Equal(t, runtime.GOMAXPROCS(0), metadata["gomaxprocs"])
+	assert.Equal(t, runtime.NumCPU(), metadata["num_cpu"])
+	assert.Len(t, metadata, 3)
+}
@@ -0,0 +1,425 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validConfig returns a config that passes Validate
+func validConfig() *Config {
+	return &Config{
+		JWTSecret: "k3Yx9Qp2Lm7Vn4Rt8Ws1Zc6Bf0Hd5Jg",
+		RabbitMQ:  RabbitMQConfig{RoutingKeys: routingKeys("")},
+	}
+}
+
+func TestValidate_RoutingKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		routingKeys map[string]string
+		errText     string
+	}{
+		{
+			name:        "Defaults are valid",
+			routingKeys: routingKeys(""),
+		},
+		{
+			name:        "Override is valid",
+			routingKeys: routingKeys("user.created=auth.user.created"),
+		},
+		{
+			name:        "Missing event type",
+			routingKeys: map[string]string{"user.created": "user.created"},
+			errText:     "no routing key configured for event type user.deleted",
+		},
+		{
+			name:        "Empty routing key",
+			routingKeys: routingKeys("user.deleted="),
+			errText:     "no routing key configured for event type user.deleted",
+		},
+		{
+			name:        "Unknown event type",
+			routingKeys: routingKeys("user.renamed=user.renamed"),
+			errText:     "unknown event type user.renamed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.RabbitMQ.RoutingKeys = tt.routingKeys
+
+			err := cfg.Validate()
+
+			if tt.errText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestValidate_ContentTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentTypes string
+		errText      string
+	}{
+		{
+			name: "No overrides",
+		},
+		{
+			name:         "Override is valid",
+			contentTypes: "user.created=application/vnd.user-created+json",
+		},
+		{
+			name:         "Empty content type",
+			contentTypes: "user.created=",
+			errText:      "empty content type configured for event type user.created",
+		},
+		{
+			name:         "Unknown event type",
+			contentTypes: "user.renamed=application/json",
+			errText:      "content type configured for unknown event type user.renamed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.RabbitMQ.ContentTypes = parsePairs("RABBITMQ_CONTENT_TYPES", tt.contentTypes, "event=content-type")
+
+			err := cfg.Validate()
+
+			if tt.errText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestValidate_AuthPublicMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []string
+		errText string
+	}{
+		{name: "Defaults are valid", methods: DefaultAuthPublicMethods},
+		{name: "No public methods", methods: nil},
+		{name: "Missing leading slash", methods: []string{"authpb.AuthService/Login"}, errText: `invalid method name "authpb.AuthService/Login"`},
+		{name: "Missing method", methods: []string{"/authpb.AuthService/"}, errText: `invalid method name "/authpb.AuthService/"`},
+		{name: "Short method name", methods: []string{"Login"}, errText: `invalid method name "Login"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.AuthPublicMethods = tt.methods
+
+			err := cfg.Validate()
+
+			if tt.errText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestValidate_TenantSchemas(t *testing.T) {
+	tests := []struct {
+		name        string
+		multiTenant bool
+		schemas     []string
+		errText     string
+	}{
+		{name: "Single tenant without schemas", multiTenant: false},
+		{name: "Multi tenant with schemas", multiTenant: true, schemas: []string{"acme", "globex"}},
+		{name: "Multi tenant without schemas", multiTenant: true, errText: "at least one tenant schema is required"},
+		{name: "Invalid schema", multiTenant: true, schemas: []string{"acme", "Acme.users"}, errText: `invalid tenant schema "Acme.users"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.MultiTenant = tt.multiTenant
+			cfg.TenantSchemas = tt.schemas
+
+			err := cfg.Validate()
+
+			if tt.errText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestRoutingKeys_Overrides(t *testing.T) {
+	keys := routingKeys("user.created = auth.user.created")
+
+	assert.Equal(t, "auth.user.created", keys["user.created"])
+	assert.Equal(t, "user.deleted", keys["user.deleted"])
+	assert.Equal(t, "user.created", DefaultRoutingKeys["user.created"], "defaults must not be mutated")
+}
+
+func TestParseSizeLimits(t *testing.T) {
+	limits := parseSizeLimits("GRPC_MAX_REQUEST_BYTES", defaultMaxRequestBytes)
+
+	assert.Equal(t, map[string]int{"/authpb.AuthService/Login": 4096, "/authpb.AuthService/Register": 8192}, limits)
+	assert.Empty(t, parseSizeLimits("GRPC_MAX_REQUEST_BYTES", ""))
+	assert.Panics(t, func() { parseSizeLimits("GRPC_MAX_REQUEST_BYTES", "/authpb.AuthService/Login=big") })
+	assert.Panics(t, func() { parseSizeLimits("GRPC_MAX_REQUEST_BYTES", "/authpb.AuthService/Login=0") })
+}
+
+func TestRoutingKeys_MalformedPanics(t *testing.T) {
+	assert.Panics(t, func() { routingKeys("user.created") })
+}
+
+func TestValidate_JWTSecret(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString("jspoBKPTOgw+T1nJJ/XlNEoW+LONfXuY/WWCaPcTalc=")
+
+	tests := []struct {
+		name         string
+		secret       string
+		secretBase64 string
+		errText      string
+	}{
+		{
+			name:   "Raw secret",
+			secret: "k3Yx9Qp2Lm7Vn4Rt8Ws1Zc6Bf0Hd5Jg",
+		},
+		{
+			name:         "Base64 secret",
+			secretBase64: base64.StdEncoding.EncodeToString(secret),
+		},
+		{
+			name:         "Invalid base64",
+			secretBase64: "not base64!",
+			errText:      "JWT_SECRET_BASE64 is not valid base64",
+		},
+		{
+			name:         "Base64 secret too short",
+			secretBase64: base64.StdEncoding.EncodeToString(secret[:16]),
+			errText:      "must decode to at least 32 bytes",
+		},
+		{
+			name:         "Base64 secret of zero bytes",
+			secretBase64: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+			errText:      "JWT_SECRET_BASE64 key entropy is too low",
+		},
+		{
+			name:         "Base64 secret with a repeated pattern",
+			secretBase64: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("\x01\x02\x03\x04", 8))),
+			errText:      "JWT_SECRET_BASE64 key entropy is too low",
+		},
+		{
+			name:         "Both set",
+			secret:       "k3Yx9Qp2Lm7Vn4Rt8Ws1Zc6Bf0Hd5Jg",
+			secretBase64: base64.StdEncoding.EncodeToString(secret),
+			errText:      "mutually exclusive",
+		},
+		{
+			name:    "Neither set",
+			errText: "must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.JWTSecret = tt.secret
+			cfg.JWTSecretBase64 = tt.secretBase64
+
+			err := cfg.Validate()
+
+			if tt.errText == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+		})
+	}
+}
+
+func TestValidateDetailed_MultipleErrors(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWTSecret = ""
+	cfg.JWTSecretBase64 = "not base64!"
+	cfg.BootstrapAdmin = BootstrapAdminConfig{Email: "admin@example.com"}
+	cfg.RabbitMQ.RoutingKeys = routingKeys("user.deleted=,user.renamed=user.renamed")
+
+	fieldErrors := cfg.ValidateDetailed()
+
+	assert.Equal(t, []FieldError{
+		{Field: "JWT_SECRET_BASE64", Message: "JWT_SECRET_BASE64 is not valid base64: illegal base64 data at input byte 3"},
+		{Field: "BOOTSTRAP_ADMIN_PASSWORD", Message: "BOOTSTRAP_ADMIN_EMAIL and BOOTSTRAP_ADMIN_PASSWORD must be set together"},
+		{Field: "RABBITMQ_ROUTING_KEYS", Message: "no routing key configured for event type user.deleted"},
+		{Field: "RABBITMQ_ROUTING_KEYS", Message: "routing key configured for unknown event type user.renamed"},
+	}, fieldErrors)
+
+	err := cfg.Validate()
+	for _, fieldError := range fieldErrors {
+		assert.ErrorContains(t, err, fieldError.Message)
+	}
+	assert.Len(t, strings.Split(err.Error(), "\n"), len(fieldErrors))
+}
+
+func TestValidateDetailed_Valid(t *testing.T) {
+	cfg := validConfig()
+
+	assert.Empty(t, cfg.ValidateDetailed())
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestJWTSigningKey(t *testing.T) {
+	secret := []byte(strings.Repeat("\xff\x00", 16))
+
+	assert.Equal(t, secret, (&Config{JWTSecretBase64: base64.StdEncoding.EncodeToString(secret)}).JWTSigningKey())
+	assert.Equal(t, []byte("raw-secret"), (&Config{JWTSecret: "raw-secret"}).JWTSigningKey())
+	assert.Nil(t, (&Config{}).JWTSigningKey())
+	assert.Nil(t, (&Config{JWTSecretBase64: "not base64!"}).JWTSigningKey())
+}
+
+func TestValidate_BootstrapAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		admin   BootstrapAdminConfig
+		wantErr bool
+	}{
+		{name: "Disabled", admin: BootstrapAdminConfig{}},
+		{name: "Enabled", admin: BootstrapAdminConfig{Email: "admin@example.com", Password: "change-me-now"}},
+		{name: "Email only", admin: BootstrapAdminConfig{Email: "admin@example.com"}, wantErr: true},
+		{name: "Password only", admin: BootstrapAdminConfig{Password: "change-me-now"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.BootstrapAdmin = tt.admin
+
+			err := cfg.Validate()
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "must be set together")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.admin.Email != "", cfg.BootstrapAdmin.Enabled())
+			}
+		})
+	}
+}
+
+func TestLoadLogConfig_EnvironmentDefaults(t *testing.T) {
+	tests := []struct {
+		environment string
+		expected    string
+	}{
+		{environment: EnvironmentDev, expected: "debug"},
+		{environment: EnvironmentStaging, expected: "info"},
+		{environment: EnvironmentProd, expected: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			unsetEnv(t, "LOG_LEVEL")
+
+			assert.Equal(t, tt.expected, loadLogConfig(tt.environment).Level)
+		})
+	}
+}
+
+func TestLoadLogConfig_Masking(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		value       string
+		expected    bool
+	}{
+		{name: "On by default", environment: EnvironmentDev, value: "", expected: true},
+		{name: "Off in dev", environment: EnvironmentDev, value: "false", expected: false},
+		{name: "Off in staging", environment: EnvironmentStaging, value: "false", expected: false},
+		{name: "Forced on in prod", environment: EnvironmentProd, value: "false", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOG_MASKING_ENABLED", tt.value)
+
+			assert.Equal(t, tt.expected, loadLogConfig(tt.environment).MaskingActive())
+		})
+	}
+}
+
+func TestLoadLogConfig_ExplicitLevelWins(t *testing.T) {
+	for _, environment := range []string{EnvironmentDev, EnvironmentStaging, EnvironmentProd} {
+		t.Run(environment, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", "warn")
+
+			assert.Equal(t, "warn", loadLogConfig(environment).Level)
+		})
+	}
+}
+
+// unsetEnv makes key unset for the test, Setenv restores the original value afterwards
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+func TestValueSources_EnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=warn\nMETRICS_PORT=9191\n"), 0o600))
+	t.Setenv("LOG_LEVEL", "debug")
+	unsetEnv(t, "METRICS_PORT")
+	unsetEnv(t, "JWT_KEY_ID")
+
+	sources := valueSources(loadEnvFile(path))
+
+	assert.Equal(t, SourceEnv, sources["LOG_LEVEL"])
+	assert.Equal(t, "debug", os.Getenv("LOG_LEVEL"), "the environment must win over the file")
+	assert.Equal(t, SourceFile, sources["METRICS_PORT"])
+	assert.Equal(t, "9191", os.Getenv("METRICS_PORT"))
+	assert.Equal(t, SourceDefault, sources["JWT_KEY_ID"])
+}
+
+func TestLoadEnvFile_MissingFile(t *testing.T) {
+	assert.Empty(t, loadEnvFile(filepath.Join(t.TempDir(), ".env")))
+}
+
+func TestLogSources_RedactsSecrets(t *testing.T) {
+	t.Setenv("JWT_SECRET", "k3Yx9Qp2Lm7Vn4Rt8Ws1Zc6Bf0Hd5Jg")
+	t.Setenv("LOG_LEVEL", "debug")
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	cfg := &Config{Sources: map[string]ValueSource{
+		"JWT_SECRET": SourceEnv,
+		"LOG_LEVEL":  SourceEnv,
+		"JWT_KEY_ID": SourceDefault,
+	}}
+
+	cfg.LogSources()
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, map[string]any{"source": "env", "value": "***"}, record["JWT_SECRET"])
+	assert.Equal(t, map[string]any{"source": "env", "value": "debug"}, record["LOG_LEVEL"])
+	assert.Equal(t, map[string]any{"source": "default"}, record["JWT_KEY_ID"])
+	assert.NotContains(t, buf.String(), "k3Yx9Qp2")
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// envFile is the optional dotenv file read by LoadConfig
+const envFile = ".env"
+
+// ValueSource tells where the effective value of a configuration key came from
+type ValueSource string
+
+const (
+	SourceDefault ValueSource = "default"
+	SourceFile    ValueSource = "file"
+	SourceEnv     ValueSource = "env"
+)
+
+// sourceKeys are the variables whose source LoadConfig records, in the order they are logged
+var sourceKeys = []string{
+	"ENVIRONMENT",
+	"LOG_LEVEL",
+	"LOG_MASKING_ENABLED",
+	"AUTH_SERVICE_PORT",
+	"METRICS_PORT",
+	"AUTH_DB_HOST",
+	"AUTH_DB_PORT",
+	"AUTH_DB_NAME",
+	"AUTH_DB_USER",
+	"AUTH_DB_PASSWORD",
+	"AUTH_DB_READ_REPLICA_HOST",
+	"RABBITMQ_URL",
+	"RABBITMQ_EXCHANGE",
+	"RABBITMQ_REQUIRED",
+	"JWT_SECRET",
+	"JWT_SECRET_BASE64",
+	"JWT_KEY_ID",
+	"PASSWORD_HASH_ALGO",
+	"MAINTENANCE_MODE",
+	"MULTI_TENANT",
+	"ENABLE_TLS",
+}
+
+// secretKeys are never logged by value, RABBITMQ_URL carries broker credentials
+var secretKeys = map[string]bool{
+	"AUTH_DB_PASSWORD":  true,
+	"RABBITMQ_URL":      true,
+	"JWT_SECRET":        true,
+	"JWT_SECRET_BASE64": true,
+}
+
+// loadEnvFile sets the variables defined in path that the environment does not already set,
+// so the environment takes precedence like with godotenv.Load
+// It returns the names of the variables taken from the file, a missing file sets nothing
+func loadEnvFile(path string) map[string]bool {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil
+	}
+
+	fromFile := make(map[string]bool, len(values))
+	for key, value := range values {
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err == nil {
+			fromFile[key] = true
+		}
+	}
+	return fromFile
+}
+
+// valueSources returns the source of every key in sourceKeys, fromFile is the result of loadEnvFile
+func valueSources(fromFile map[string]bool) map[string]ValueSource {
+	sources := make(map[string]ValueSource, len(sourceKeys))
+	for _, key := range sourceKeys {
+		switch _, set := os.LookupEnv(key); {
+		case fromFile[key]:
+			sources[key] = SourceFile
+		case set:
+			sources[key] = SourceEnv
+		default:
+			sources[key] = SourceDefault
+		}
+	}
+	return sources
+}
+
+// LogSources logs at debug level where each recorded key got its value from
+// Values set by the file or environment are included with secrets redacted, defaults only show their source
+func (c *Config) LogSources() {
+	attrs := make([]any, 0, len(sourceKeys))
+	for _, key := range sourceKeys {
+		source, ok := c.Sources[key]
+		if !ok {
+			continue
+		}
+		fields := []any{slog.String("source", string(source))}
+		if source != SourceDefault {
+			value := os.Getenv(key)
+			if secretKeys[key] {
+				value = "***"
+			}
+			fields = append(fields, slog.String("value", value))
+		}
+		attrs = append(attrs, slog.Group(key, fields...))
+	}
+	slog.Debug("Configuration sources", attrs...)
+}
@@ -0,0 +1,64 @@
+// Package features toggles experimental behaviors from the environment without code changes
+package features
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+)
+
+// LastLoginTracking records the time of each successful login on the user
+const LastLoginTracking = "last_login_tracking"
+
+// defaults holds the state of known flags that are not set in the environment
+var defaults = map[string]bool{
+	LastLoginTracking: true,
+}
+
+// Flags is a set of feature flags, the zero value has every known flag at its default
+type Flags struct {
+	enabled map[string]bool
+}
+
+// New returns flags with the given states, flags missing from enabled keep their defaults
+func New(enabled map[string]bool) Flags {
+	flags := Flags{enabled: make(map[string]bool, len(enabled))}
+	for name, on := range enabled {
+		flags.enabled[name] = on
+	}
+	return flags
+}
+
+// Load reads flags from FEATURE_FLAGS, a comma-separated list of name=bool pairs
+// Known flags can also be set one by one with FEATURE_<NAME>, which wins over FEATURE_FLAGS
+// Values that are not booleans are ignored
+func Load() Flags {
+	enabled := map[string]bool{}
+	for name, value := range utils.GetEnvMap("FEATURE_FLAGS", nil) {
+		if on, err := strconv.ParseBool(value); err == nil {
+			enabled[name] = on
+		}
+	}
+	for name := range defaults {
+		current, ok := enabled[name]
+		if !ok {
+			current = defaults[name]
+		}
+		enabled[name] = utils.GetEnvBool(EnvKey(name), current)
+	}
+	return New(enabled)
+}
+
+// EnvKey returns the environment variable that sets the flag name on its own
+func EnvKey(name string) string {
+	return "FEATURE_" + strings.ToUpper(name)
+}
+
+// Enabled reports whether the flag name is on, unknown flags that were never set are off
+func (f Flags) Enabled(name string) bool {
+	if on, ok := f.enabled[name]; ok {
+		return on
+	}
+	return defaults[name]
+}
@@ -0,0 +1,73 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags_ZeroValueUsesDefaults(t *testing.T) {
+	var flags Flags
+
+	assert.True(t, flags.Enabled(LastLoginTracking))
+	assert.False(t, flags.Enabled("unknown"))
+}
+
+func TestNew_OverridesDefaults(t *testing.T) {
+	flags := New(map[string]bool{LastLoginTracking: false, "beta": true})
+
+	assert.False(t, flags.Enabled(LastLoginTracking))
+	assert.True(t, flags.Enabled("beta"))
+	assert.False(t, flags.Enabled("unknown"))
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    string
+		single   string
+		expected map[string]bool
+	}{
+		{
+			name:     "Nothing set",
+			expected: map[string]bool{LastLoginTracking: true, "beta": false},
+		},
+		{
+			name:     "Flag list",
+			flags:    "last_login_tracking=false, beta=true",
+			expected: map[string]bool{LastLoginTracking: false, "beta": true},
+		},
+		{
+			name:     "Single flag wins over the list",
+			flags:    "last_login_tracking=false",
+			single:   "true",
+			expected: map[string]bool{LastLoginTracking: true},
+		},
+		{
+			name:     "Invalid values are ignored",
+			flags:    "last_login_tracking=maybe,beta=yes",
+			expected: map[string]bool{LastLoginTracking: true, "beta": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.flags != "" {
+				t.Setenv("FEATURE_FLAGS", tt.flags)
+			}
+			if tt.single != "" {
+				t.Setenv(EnvKey(LastLoginTracking), tt.single)
+			}
+
+			flags := Load()
+
+			for name, expected := range tt.expected {
+				assert.Equal(t, expected, flags.Enabled(name), name)
+			}
+		})
+	}
+}
+
+func TestEnvKey(t *testing.T) {
+	assert.Equal(t, "FEATURE_LAST_LOGIN_TRACKING", EnvKey(LastLoginTracking))
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+)
+
+type (
+	requestStartKey struct{}
+	logCtxKey       struct{}
+)
+
+// maxUserAgentLength caps the user agent added to log records
+const maxUserAgentLength = 256
+
+// LogCtx holds request-scoped fields that the context handler adds to every record
+// Empty fields are omitted, Email and ClientIP are masked and UserAgent is truncated on emission
+type LogCtx struct {
+	RequestID string
+	// TraceID is the distributed trace id, emitted with its ShortCorrelation as corr_id
+	TraceID string
+	UserID  string
+	Email   string
+	// ClientIP is the caller address, a bare IP or ip:port
+	ClientIP  string
+	UserAgent string
+}
+
+// WithLogCtx stores lc in the context, replacing any fields stored earlier
+func WithLogCtx(ctx context.Context, lc LogCtx) context.Context {
+	return context.WithValue(ctx, logCtxKey{}, lc)
+}
+
+// FromContext returns the LogCtx stored in the context, or a zero LogCtx
+func FromContext(ctx context.Context) LogCtx {
+	lc, _ := ctx.Value(logCtxKey{}).(LogCtx)
+	return lc
+}
+
+// WithRequestID stores the request id in the logging context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	lc := FromContext(ctx)
+	lc.RequestID = requestID
+	return WithLogCtx(ctx, lc)
+}
+
+// WithTraceID stores the distributed trace id in the logging context
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	lc := FromContext(ctx)
+	lc.TraceID = traceID
+	return WithLogCtx(ctx, lc)
+}
+
+// shortCorrelationLength is the number of hex characters in a short correlation id
+const shortCorrelationLength = 8
+
+// ShortCorrelation derives a short correlation id from traceID for human-facing logs
+// The same trace id always yields the same id, an empty trace id yields an empty id
+func ShortCorrelation(traceID string) string {
+	if traceID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(traceID))
+	return hex.EncodeToString(sum[:])[:shortCorrelationLength]
+}
+
+// WithUserID stores the user id in the logging context
+func WithUserID(ctx context.Context, userID string) context.Context {
+	lc := FromContext(ctx)
+	lc.UserID = userID
+	return WithLogCtx(ctx, lc)
+}
+
+// WithEmail stores the user email in the logging context
+func WithEmail(ctx context.Context, email string) context.Context {
+	lc := FromContext(ctx)
+	lc.Email = email
+	return WithLogCtx(ctx, lc)
+}
+
+// WithClientIP stores the caller address, a bare IP or ip:port, in the logging context
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	lc := FromContext(ctx)
+	lc.ClientIP = clientIP
+	return WithLogCtx(ctx, lc)
+}
+
+// WithUserAgent stores the caller user agent in the logging context
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	lc := FromContext(ctx)
+	lc.UserAgent = userAgent
+	return WithLogCtx(ctx, lc)
+}
+
+// SanitizedContext returns a child of ctx whose logging context has the email and client address cleared
+// Request and user ids are kept so records logged through it still correlate with the request
+// Use it before handing ctx to code that should not see personal data
+func SanitizedContext(ctx context.Context) context.Context {
+	lc := FromContext(ctx)
+	lc.Email = ""
+	lc.ClientIP = ""
+	return WithLogCtx(ctx, lc)
+}
+
+// attrs converts the non-empty fields to log attributes, masking them unless mask is false
+func (lc LogCtx) attrs(mask bool) []slog.Attr {
+	var attrs []slog.Attr
+	if lc.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", lc.RequestID))
+	}
+	if lc.TraceID != "" {
+		attrs = append(attrs, slog.String("trace_id", lc.TraceID), slog.String("corr_id", ShortCorrelation(lc.TraceID)))
+	}
+	if lc.UserID != "" {
+		attrs = append(attrs, slog.String("user_id", lc.UserID))
+	}
+	if lc.Email != "" {
+		email := lc.Email
+		if mask {
+			email = utils.MaskEmail(email)
+		}
+		attrs = append(attrs, slog.String("email", email))
+	}
+	if lc.ClientIP != "" {
+		clientIP := lc.ClientIP
+		if mask {
+			clientIP = utils.MaskIP(clientIP)
+		}
+		attrs = append(attrs, slog.String("client_ip", clientIP))
+	}
+	if lc.UserAgent != "" {
+		userAgent := lc.UserAgent
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+		attrs = append(attrs, slog.String("user_agent", userAgent))
+	}
+	return attrs
+}
+
+// WithRequestStart stores the current time in the context as the request start
+func WithRequestStart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestStartKey{}, time.Now())
+}
+
+// RequestStart returns the request start stored by WithRequestStart
+func RequestStart(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(requestStartKey{}).(time.Time)
+	return start, ok
+}
+
+// LogRequestDuration logs msg at INFO with duration_ms measured from the request start
+// If no start is stored the record is emitted without duration_ms
+func LogRequestDuration(ctx context.Context, msg string, attrs ...any) {
+	LogRequestDurationAt(ctx, slog.LevelInfo, msg, attrs...)
+}
+
+// LogRequestDurationAt is LogRequestDuration with an explicit level
+func LogRequestDurationAt(ctx context.Context, level slog.Level, msg string, attrs ...any) {
+	if start, ok := RequestStart(ctx); ok {
+		attrs = append(attrs, slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000))
+	}
+	slog.Log(ctx, level, msg, attrs...)
+}
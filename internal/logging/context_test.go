@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureDefault installs a JSON logger over a buffer as the slog default for one test
+func captureDefault(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(createLogger(config.LogConfig{Level: "debug"}, &buf))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestLogRequestDuration(t *testing.T) {
+	// Arrange
+	buf := captureDefault(t)
+	ctx := WithRequestStart(context.Background())
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	LogRequestDuration(ctx, "request completed", slog.String("method", "/authpb.AuthService/Login"))
+
+	// Assert
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "request completed", entry["msg"])
+	assert.Equal(t, "/authpb.AuthService/Login", entry["method"])
+	require.Contains(t, entry, "duration_ms")
+	duration := entry["duration_ms"].(float64)
+	assert.GreaterOrEqual(t, duration, 20.0)
+	assert.Less(t, duration, 5000.0)
+}
+
+func TestLogRequestDuration_WithoutStart(t *testing.T) {
+	// Arrange
+	buf := captureDefault(t)
+
+	// Act
+	LogRequestDuration(context.Background(), "request completed")
+
+	// Assert
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "duration_ms")
+}
+
+func TestSanitizedContext(t *testing.T) {
+	// Arrange
+	buf := captureDefault(t)
+	ctx := WithClientIP(WithEmail(WithRequestID(context.Background(), "req-1"), "john.doe@example.com"), "203.0.113.7:5000")
+
+	// Act
+	sanitized := SanitizedContext(ctx)
+	slog.InfoContext(sanitized, "callback invoked")
+
+	// Assert
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.NotContains(t, entry, "email")
+	assert.NotContains(t, entry, "client_ip")
+	assert.Equal(t, "john.doe@example.com", FromContext(ctx).Email, "the parent context must not change")
+}
+
+func TestShortCorrelation_Deterministic(t *testing.T) {
+	// Act
+	first := ShortCorrelation("4bf92f3577b34da6a3ce929d0e0e4736")
+	second := ShortCorrelation("4bf92f3577b34da6a3ce929d0e0e4736")
+
+	// Assert
+	assert.Len(t, first, 8)
+	assert.Equal(t, first, second)
+	assert.Empty(t, ShortCorrelation(""))
+}
+
+func TestShortCorrelation_RarelyCollides(t *testing.T) {
+	// Arrange
+	const n = 10000
+	seen := make(map[string]struct{}, n)
+
+	// Act
+	for i := 0; i < n; i++ {
+		seen[ShortCorrelation(fmt.Sprintf("trace-%d", i))] = struct{}{}
+	}
+
+	// Assert
+	assert.GreaterOrEqual(t, len(seen), n-1, "distinct trace ids should map to distinct short ids")
+}
+
+func TestTraceID_EmittedWithCorrelationID(t *testing.T) {
+	// Arrange
+	buf := captureDefault(t)
+	ctx := WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	// Act
+	slog.InfoContext(ctx, "request handled")
+
+	// Assert
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	assert.Equal(t, ShortCorrelation("4bf92f3577b34da6a3ce929d0e0e4736"), entry["corr_id"])
+}
+
+func TestTraceID_AbsentWithoutTrace(t *testing.T) {
+	// Arrange
+	buf := captureDefault(t)
+
+	// Act
+	slog.InfoContext(WithRequestID(context.Background(), "req-1"), "request handled")
+
+	// Assert
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "trace_id")
+	assert.NotContains(t, entry, "corr_id")
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// contextHandler adds LogCtx fields from the record context to every record
+//
+// Precedence is explicit attributes first: attributes passed at the call site override
+// attributes added with Logger.With, and both override context fields of the same key.
+// Each key is emitted once, in the order context fields, With attributes, call-site attributes.
+// Context fields belong to the innermost group opened with WithGroup, the same rules apply
+// there, and attributes of enclosing scopes are delegated to the wrapped handler as-is.
+// With an allowlist, attributes outside it are dropped before they reach the wrapped handler,
+// which therefore only ever sees the record time, level, message and source plus allowed fields.
+type contextHandler struct {
+	next slog.Handler
+	// attrs are the With attributes of the current group scope
+	attrs []slog.Attr
+	// unmasked emits context fields as stored, see config.LogConfig.MaskingActive
+	unmasked bool
+	// allowlist restricts the emitted attributes, see config.LogConfig.FieldAllowlist
+	allowlist fieldAllowlist
+	// prefix is the dotted path of the groups opened with WithGroup, matched against allowlist
+	prefix string
+}
+
+// NewContextHandler wraps next so records carry the LogCtx fields of their context
+func NewContextHandler(next slog.Handler) slog.Handler {
+	return &contextHandler{next: next}
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(h.allowlist.filter(h.prefix, dedupAttrs(FromContext(ctx).attrs(!h.unmasked), h.attrs, recordAttrs))...)
+	return h.next.Handle(ctx, out)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(slices.Clip(h.attrs), attrs...)
+	return &clone
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := h.next
+	if attrs := h.allowlist.filter(h.prefix, h.attrs); len(attrs) > 0 {
+		next = next.WithAttrs(attrs)
+	}
+	return &contextHandler{next: next.WithGroup(name), unmasked: h.unmasked, allowlist: h.allowlist, prefix: h.prefix + name + "."}
+}
+
+// dedupAttrs merges attribute layers so that each key appears once
+// A key keeps the position of its first occurrence and the value of its last
+func dedupAttrs(layers ...[]slog.Attr) []slog.Attr {
+	var merged []slog.Attr
+	index := make(map[string]int)
+	for _, layer := range layers {
+		for _, a := range layer {
+			if a.Key == "" {
+				merged = append(merged, a)
+				continue
+			}
+			if i, ok := index[a.Key]; ok {
+				merged[i] = a
+				continue
+			}
+			index[a.Key] = len(merged)
+			merged = append(merged, a)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextHandler_AddsContextFields(t *testing.T) {
+	ctx := WithEmail(WithUserID(WithRequestID(context.Background(), "req-1"), "user-1"), "john.doe@example.com")
+
+	entry := logEntry(t, config.LogConfig{Level: "info"}, func(l *slog.Logger) {
+		l.InfoContext(ctx, "hello")
+	})
+
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "user-1", entry["user_id"])
+	assert.Equal(t, "j***@example.com", entry["email"])
+}
+
+func TestContextHandler_AddsClientFields(t *testing.T) {
+	ctx := WithUserAgent(WithClientIP(context.Background(), "203.0.113.7:54321"), strings.Repeat("a", maxUserAgentLength+10))
+
+	entry := logEntry(t, config.LogConfig{Level: "info"}, func(l *slog.Logger) {
+		l.InfoContext(ctx, "hello")
+	})
+
+	assert.Equal(t, "203.0.113.***:54321", entry["client_ip"])
+	assert.Equal(t, strings.Repeat("a", maxUserAgentLength), entry["user_agent"])
+}
+
+func TestContextHandler_OmitsEmptyFields(t *testing.T) {
+	entry := logEntry(t, config.LogConfig{Level: "info"}, func(l *slog.Logger) {
+		l.InfoContext(context.Background(), "hello")
+	})
+
+	assert.NotContains(t, entry, "request_id")
+	assert.NotContains(t, entry, "user_id")
+	assert.NotContains(t, entry, "email")
+	assert.NotContains(t, entry, "client_ip")
+	assert.NotContains(t, entry, "user_agent")
+}
+
+func TestContextHandler_Precedence(t *testing.T) {
+	ctx := WithUserID(context.Background(), "from-context")
+
+	tests := []struct {
+		name     string
+		log      func(*slog.Logger)
+		expected string
+	}{
+		{
+			name:     "Context only",
+			log:      func(l *slog.Logger) { l.InfoContext(ctx, "hello") },
+			expected: "from-context",
+		},
+		{
+			name:     "With attribute wins over context",
+			log:      func(l *slog.Logger) { l.With("user_id", "from-with").InfoContext(ctx, "hello") },
+			expected: "from-with",
+		},
+		{
+			name:     "Later With attribute wins over earlier",
+			log:      func(l *slog.Logger) { l.With("user_id", "first").With("user_id", "second").InfoContext(ctx, "hello") },
+			expected: "second",
+		},
+		{
+			name:     "Call-site attribute wins over With and context",
+			log:      func(l *slog.Logger) { l.With("user_id", "from-with").InfoContext(ctx, "hello", "user_id", "from-call") },
+			expected: "from-call",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(createLogger(config.LogConfig{Level: "info"}, &buf))
+
+			line := buf.String()
+			assert.Equal(t, 1, strings.Count(line, `"user_id"`), line)
+			assert.Contains(t, line, `"user_id":"`+tt.expected+`"`)
+		})
+	}
+}
+
+func TestContextHandler_KeepsAttributeOrder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "info"}, &buf).With("component", "auth")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "hello", "method", "Login")
+
+	line := buf.String()
+	requestID := strings.Index(line, `"request_id"`)
+	component := strings.Index(line, `"component"`)
+	method := strings.Index(line, `"method"`)
+	assert.Less(t, requestID, component)
+	assert.Less(t, component, method)
+}
+
+func TestContextHandler_WithGroupKeepsEarlierAttributes(t *testing.T) {
+	entry := logEntry(t, config.LogConfig{Level: "info"}, func(l *slog.Logger) {
+		l.With("component", "auth").WithGroup("request").InfoContext(context.Background(), "hello", "method", "Login")
+	})
+
+	assert.Equal(t, "auth", entry["component"])
+	assert.Equal(t, map[string]any{"method": "Login"}, entry["request"])
+}
+
+func TestContextHandler_WithGroupNestsContextFields(t *testing.T) {
+	ctx := WithUserID(WithRequestID(context.Background(), "req-1"), "user-1")
+
+	entry := logEntry(t, config.LogConfig{Level: "info"}, func(l *slog.Logger) {
+		l.With("component", "auth").WithGroup("request").InfoContext(ctx, "hello", "method", "Login")
+	})
+
+	assert.Equal(t, "auth", entry["component"])
+	assert.NotContains(t, entry, "request_id")
+	assert.NotContains(t, entry, "user_id")
+	assert.Equal(t, map[string]any{"request_id": "req-1", "user_id": "user-1", "method": "Login"}, entry["request"])
+}
+
+func TestContextHandler_WithGroupPrecedence(t *testing.T) {
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "info"}, &buf).WithGroup("request").With("user_id", "from-with")
+
+	logger.InfoContext(WithUserID(context.Background(), "from-context"), "hello")
+
+	line := buf.String()
+	assert.Equal(t, 1, strings.Count(line, `"user_id"`), line)
+	assert.Contains(t, line, `"request":{"user_id":"from-with"}`)
+}
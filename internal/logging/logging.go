@@ -0,0 +1,266 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+)
+
+// DefaultTimestampKey is the record timestamp field name used when none is configured
+const DefaultTimestampKey = "@timestamp"
+
+// init installs a JSON logger with context field extraction as the slog default,
+// so email masking and context fields apply even if InitLogging is never called.
+// The stock default handler is not wrapped: it writes through the log package,
+// which SetDefault redirects back into slog, and wrapping it would loop.
+func init() {
+	installFallbackLogger(os.Stdout)
+}
+
+// installFallbackLogger sets the pre-InitLogging default logger writing to w
+func installFallbackLogger(w io.Writer) {
+	slog.SetDefault(createLogger(config.LogConfig{}, w))
+}
+
+// InitLogging configures the default slog logger from config
+// and the rate WithSamplingDecision samples requests at
+func InitLogging(cfg config.LogConfig) {
+	if cfg.SamplingEnabled() {
+		setSampleRate(cfg.SampleRate)
+	} else {
+		setSampleRate(1)
+	}
+	slog.SetDefault(createLoggerWithStreams(cfg, os.Stdout, os.Stderr))
+}
+
+// createLogger builds a JSON logger writing every record to w
+func createLogger(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	return createLoggerWithStreams(cfg, w, w)
+}
+
+// createLoggerWithStreams builds a JSON logger that adds context fields to records
+// WARN and ERROR records go to errW when cfg.ErrorOutputToStderr is set, everything else to w
+// With cfg.DualOutput every record is also written to errW in text form for humans
+// ERROR records are also posted to cfg.ErrorWebhookURL in the background when it is set
+// With sampling enabled, INFO and DEBUG records of sampled-out requests are dropped, see samplingHandler
+// With cfg.IncludeHostname every record carries the hostname, resolved once here
+func createLoggerWithStreams(cfg config.LogConfig, w, errW io.Writer) *slog.Logger {
+	var handler slog.Handler = newJSONHandler(cfg, w)
+	if cfg.ErrorOutputToStderr {
+		handler = &levelSplitHandler{
+			normal: handler,
+			errors: newJSONHandler(cfg, errW),
+		}
+	}
+	if cfg.DualOutput {
+		handler = &fanoutHandler{handlers: []slog.Handler{handler, newTextHandler(cfg, errW)}}
+	}
+	if cfg.ErrorWebhookURL != "" {
+		handler = newWebhookHandler(handler, newErrorWebhook(cfg.ErrorWebhookURL))
+	}
+	handler = &contextHandler{next: handler, unmasked: !cfg.MaskingActive(), allowlist: newFieldAllowlist(cfg.FieldAllowlist)}
+	if cfg.SamplingEnabled() {
+		handler = &samplingHandler{next: handler}
+	}
+	logger := slog.New(handler)
+	if cfg.IncludeHostname {
+		if hostname := getHostname(); hostname != "" {
+			logger = logger.With(slog.String(HostnameKey, hostname))
+		}
+	}
+	return logger
+}
+
+// HostnameKey is the attribute holding the machine hostname, see config.LogConfig.IncludeHostname
+const HostnameKey = "hostname"
+
+// getHostname returns the machine hostname, or an empty string when it cannot be resolved
+func getHostname() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+// newJSONHandler builds the base JSON handler writing to w
+func newJSONHandler(cfg config.LogConfig, w io.Writer) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       parseLevel(cfg.Level),
+		AddSource:   cfg.AddSource,
+		ReplaceAttr: replaceAttr(cfg),
+	})
+}
+
+// newTextHandler builds the human-readable handler of dual output, masked and formatted like the JSON one
+func newTextHandler(cfg config.LogConfig, w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       parseLevel(cfg.Level),
+		AddSource:   cfg.AddSource,
+		ReplaceAttr: replaceAttr(cfg),
+	})
+}
+
+// tokenKeys are attribute keys whose values always hold credentials
+var tokenKeys = map[string]struct{}{
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"id_token":      {},
+	"jwt":           {},
+	"authorization": {},
+}
+
+// secretKeys are attribute keys whose values are replaced entirely, whatever their type
+var secretKeys = map[string]struct{}{
+	"password":      {},
+	"password_hash": {},
+	"secret":        {},
+}
+
+// fieldAllowlist is the set of keys from config.LogConfig.FieldAllowlist, nil allows every field
+type fieldAllowlist map[string]struct{}
+
+func newFieldAllowlist(keys []string) fieldAllowlist {
+	if len(keys) == 0 {
+		return nil
+	}
+	allowlist := make(fieldAllowlist, len(keys))
+	for _, key := range keys {
+		allowlist[key] = struct{}{}
+	}
+	return allowlist
+}
+
+// allows reports whether the field at the dotted path may be emitted, a listed group admits all of its fields
+func (l fieldAllowlist) allows(path string) bool {
+	if l == nil {
+		return true
+	}
+	if _, ok := l[path]; ok {
+		return true
+	}
+	if group, _, nested := strings.Cut(path, "."); nested {
+		_, ok := l[group]
+		return ok
+	}
+	return false
+}
+
+// filter returns the attrs allowed under prefix, the dotted path of their enclosing groups ending in a dot
+// Groups that are not listed themselves keep only their allowed fields and are dropped when none remain
+func (l fieldAllowlist) filter(prefix string, attrs []slog.Attr) []slog.Attr {
+	if l == nil {
+		return attrs
+	}
+
+	var kept []slog.Attr
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() != slog.KindGroup {
+			if a.Key != "" && l.allows(prefix+a.Key) {
+				kept = append(kept, a)
+			}
+			continue
+		}
+
+		if a.Key != "" && l.allows(prefix+a.Key) {
+			kept = append(kept, a)
+			continue
+		}
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		if nested := l.filter(groupPrefix, a.Value.Group()); len(nested) > 0 {
+			kept = append(kept, slog.Attr{Key: a.Key, Value: slog.GroupValue(nested...)})
+		}
+	}
+	return kept
+}
+
+// replaceAttr renames and formats the record timestamp according to config
+// and masks credentials: values of secretKeys are redacted, values of tokenKeys are always
+// masked, JWTs anywhere else in a string value, including the message, are masked in place.
+// map[string]any values are masked the same way, recursively
+// Masking is skipped when cfg.MaskingActive is false
+func replaceAttr(cfg config.LogConfig) func(groups []string, a slog.Attr) slog.Attr {
+	key := cfg.TimestampKey
+	if key == "" {
+		key = DefaultTimestampKey
+	}
+	layout := timestampLayout(cfg.TimestampFormat)
+	mask := cfg.MaskingActive()
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		// Built-in keys are only special for values of their own kind, a call-site attribute
+		// reusing the key is rendered like any other attribute
+		if len(groups) == 0 && a.Key == slog.TimeKey && a.Value.Kind() == slog.KindTime {
+			a.Key = key
+			if layout != "" {
+				a.Value = slog.StringValue(a.Value.Time().Format(layout))
+			}
+			return a
+		}
+
+		if mask {
+			a.Value = maskValue(a.Key, a.Value)
+		}
+		return a
+	}
+}
+
+// maskValue masks the value stored under key, see replaceAttr
+func maskValue(key string, value slog.Value) slog.Value {
+	if _, ok := secretKeys[strings.ToLower(key)]; ok {
+		return slog.StringValue(utils.RedactionPlaceholder())
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		if _, ok := tokenKeys[strings.ToLower(key)]; ok {
+			return slog.StringValue(utils.MaskToken(value.String()))
+		}
+		return slog.StringValue(utils.MaskJWT(value.String()))
+	case slog.KindAny:
+		if fields, ok := value.Any().(map[string]any); ok {
+			return slog.AnyValue(maskFields(fields))
+		}
+	}
+	return value
+}
+
+// maskFields returns a copy of fields with every nested value masked, fields itself is not modified
+func maskFields(fields map[string]any) map[string]any {
+	masked := make(map[string]any, len(fields))
+	for key, field := range fields {
+		masked[key] = maskValue(key, slog.AnyValue(field)).Any()
+	}
+	return masked
+}
+
+// timestampLayout resolves named formats to time layouts, other values are used as layouts
+func timestampLayout(format string) string {
+	switch format {
+	case "RFC3339":
+		return time.RFC3339
+	case "RFC3339Nano":
+		return time.RFC3339Nano
+	default:
+		return format
+	}
+}
+
+// parseLevel converts a level name to slog.Level, defaulting to INFO
+func parseLevel(level string) slog.Level {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return parsed
+}
@@ -0,0 +1,84 @@
+// Package logtest captures structured logs for assertions in tests
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+)
+
+// CapturedLogs holds the JSON records written by a test logger
+type CapturedLogs struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewTestLogger returns a DEBUG-level JSON logger with context field extraction
+// and the logs it captures
+func NewTestLogger() (*slog.Logger, *CapturedLogs) {
+	logs := &CapturedLogs{}
+	handler := slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(logging.NewContextHandler(handler)), logs
+}
+
+// Write implements io.Writer for the underlying handler
+func (c *CapturedLogs) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+// Entries returns every captured record parsed from JSON, in emission order
+// Lines that are not valid JSON are skipped
+func (c *CapturedLogs) Entries() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entries []map[string]any
+	for _, line := range strings.Split(c.buf.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Last returns the most recent record, or nil when nothing was logged
+func (c *CapturedLogs) Last() map[string]any {
+	entries := c.Entries()
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries[len(entries)-1]
+}
+
+// HasMessage reports whether any record message contains substr
+func (c *CapturedLogs) HasMessage(substr string) bool {
+	return c.FindMessage(substr) != nil
+}
+
+// FindMessage returns the first record whose message contains substr, or nil
+func (c *CapturedLogs) FindMessage(substr string) map[string]any {
+	for _, entry := range c.Entries() {
+		if msg, ok := entry[slog.MessageKey].(string); ok && strings.Contains(msg, substr) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Reset discards all captured records
+func (c *CapturedLogs) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf.Reset()
+}
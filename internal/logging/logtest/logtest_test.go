@@ -0,0 +1,74 @@
+package logtest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTestLogger_CapturesEntries(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+
+	logger.Debug("first", "n", 1)
+	logger.Error("second", "error", "boom")
+
+	entries := logs.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "first", entries[0]["msg"])
+	assert.Equal(t, "DEBUG", entries[0]["level"])
+	assert.Equal(t, 1.0, entries[0]["n"])
+	assert.Equal(t, "second", entries[1]["msg"])
+	assert.Equal(t, "boom", entries[1]["error"])
+	assert.Equal(t, entries[1], logs.Last())
+}
+
+func TestNewTestLogger_ExtractsContextFields(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+
+	logger.InfoContext(logging.WithUserID(context.Background(), "user-1"), "hello")
+
+	assert.Equal(t, "user-1", logs.Last()["user_id"])
+}
+
+func TestCapturedLogs_HasMessage(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+
+	logger.Info("User registered successfully")
+
+	assert.True(t, logs.HasMessage("registered"))
+	assert.False(t, logs.HasMessage("deleted"))
+	assert.Equal(t, "INFO", logs.FindMessage("registered")["level"])
+	assert.Nil(t, logs.FindMessage("deleted"))
+}
+
+func TestCapturedLogs_EmptyAndReset(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+	assert.Empty(t, logs.Entries())
+	assert.Nil(t, logs.Last())
+
+	logger.Info("hello")
+	logs.Reset()
+
+	assert.Empty(t, logs.Entries())
+}
+
+func TestCapturedLogs_ConcurrentWrites(t *testing.T) {
+	logger, logs := logtest.NewTestLogger()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("concurrent")
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, logs.Entries(), 50)
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+type sampledKey struct{}
+
+// SampledKey is the attribute added to records that passed sampling
+const SampledKey = "sampled"
+
+// sampleRate holds the float64 bits of the share of requests whose INFO and DEBUG records are kept
+var sampleRate atomic.Uint64
+
+func init() {
+	setSampleRate(1)
+}
+
+// setSampleRate sets the share of requests sampled in by WithSamplingDecision, clamped to 0..1
+func setSampleRate(rate float64) {
+	sampleRate.Store(math.Float64bits(min(max(rate, 0), 1)))
+}
+
+// WithSamplingDecision draws whether the INFO and DEBUG records of the request in ctx are logged
+// and stores the decision in the context, see config.LogConfig.SampleRate
+// Without sampling every request is sampled in
+func WithSamplingDecision(ctx context.Context) context.Context {
+	rate := math.Float64frombits(sampleRate.Load())
+	return WithSampled(ctx, rate >= 1 || rand.Float64() < rate)
+}
+
+// WithSampled stores an explicit sampling decision in the context
+func WithSampled(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, sampledKey{}, sampled)
+}
+
+// Sampled reports whether INFO and DEBUG records logged with ctx are kept
+// so callers can skip building expensive log attributes for sampled-out requests
+// It is true when no decision is stored, records outside a request are never sampled out
+func Sampled(ctx context.Context) bool {
+	sampled, ok := ctx.Value(sampledKey{}).(bool)
+	return !ok || sampled
+}
+
+// samplingHandler drops INFO and DEBUG records of sampled-out requests and marks the records it keeps
+// with sampled=true, so consumers can tell sampling was active. WARN and ERROR records are always kept
+type samplingHandler struct {
+	next slog.Handler
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn && !Sampled(ctx) {
+		return nil
+	}
+	r = r.Clone()
+	r.AddAttrs(slog.Bool(SampledKey, true))
+	return h.next.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entries returns every JSON line in buf decoded
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		result = append(result, entry)
+	}
+	return result
+}
+
+func TestSampling_SampledInRequestKeepsAllLevels(t *testing.T) {
+	var stdout bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "debug", SampleRate: 0.5}, &stdout, &stdout)
+	ctx := WithSampled(context.Background(), true)
+
+	logger.DebugContext(ctx, "debug")
+	logger.InfoContext(ctx, "info")
+	logger.ErrorContext(ctx, "error")
+
+	logged := entries(t, &stdout)
+	require.Len(t, logged, 3)
+	for _, entry := range logged {
+		assert.Equal(t, true, entry[SampledKey], entry["msg"])
+	}
+}
+
+func TestSampling_SampledOutRequestKeepsOnlyWarnAndError(t *testing.T) {
+	var stdout bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "debug", SampleRate: 0.5}, &stdout, &stdout)
+	ctx := WithSampled(context.Background(), false)
+
+	logger.DebugContext(ctx, "debug")
+	logger.InfoContext(ctx, "info")
+	logger.WarnContext(ctx, "warn")
+	logger.ErrorContext(ctx, "error")
+
+	logged := entries(t, &stdout)
+	require.Len(t, logged, 2)
+	assert.Equal(t, "warn", logged[0]["msg"])
+	assert.Equal(t, "error", logged[1]["msg"])
+	for _, entry := range logged {
+		assert.Equal(t, true, entry[SampledKey])
+	}
+}
+
+func TestSampling_RecordsOutsideRequestAreKept(t *testing.T) {
+	var stdout bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "info", SampleRate: 0.5}, &stdout, &stdout)
+
+	logger.Info("startup")
+
+	assert.Equal(t, []string{"startup"}, messages(t, &stdout))
+}
+
+func TestSampling_DisabledOmitsSampledField(t *testing.T) {
+	for _, rate := range []float64{0, 1} {
+		var stdout bytes.Buffer
+		logger := createLoggerWithStreams(config.LogConfig{Level: "info", SampleRate: rate}, &stdout, &stdout)
+
+		logger.InfoContext(WithSampled(context.Background(), false), "info")
+
+		logged := entries(t, &stdout)
+		require.Len(t, logged, 1, "rate %v", rate)
+		assert.NotContains(t, logged[0], SampledKey, "rate %v", rate)
+	}
+}
+
+func TestSampled_DefaultsToTrue(t *testing.T) {
+	assert.True(t, Sampled(context.Background()))
+	assert.False(t, Sampled(WithSampled(context.Background(), false)))
+}
+
+func TestWithSamplingDecision_FollowsRate(t *testing.T) {
+	t.Cleanup(func() { setSampleRate(1) })
+
+	setSampleRate(1)
+	assert.True(t, Sampled(WithSamplingDecision(context.Background())))
+
+	setSampleRate(0)
+	assert.False(t, Sampled(WithSamplingDecision(context.Background())))
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// levelSplitHandler dispatches WARN and above to errors and lower levels to normal
+type levelSplitHandler struct {
+	normal slog.Handler
+	errors slog.Handler
+}
+
+func (h *levelSplitHandler) pick(level slog.Level) slog.Handler {
+	if level >= slog.LevelWarn {
+		return h.errors
+	}
+	return h.normal
+}
+
+func (h *levelSplitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.pick(level).Enabled(ctx, level)
+}
+
+func (h *levelSplitHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.pick(r.Level).Handle(ctx, r)
+}
+
+func (h *levelSplitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelSplitHandler{normal: h.normal.WithAttrs(attrs), errors: h.errors.WithAttrs(attrs)}
+}
+
+func (h *levelSplitHandler) WithGroup(name string) slog.Handler {
+	return &levelSplitHandler{normal: h.normal.WithGroup(name), errors: h.errors.WithGroup(name)}
+}
+
+// fanoutHandler passes every record to all handlers that accept its level
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, r.Level) {
+			errs = append(errs, handler.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// messages returns the msg field of every JSON line in buf
+func messages(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var result []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		result = append(result, entry["msg"].(string))
+	}
+	return result
+}
+
+func TestErrorOutputToStderr_SplitsByLevel(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "debug", ErrorOutputToStderr: true}, &stdout, &stderr)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	assert.Equal(t, []string{"debug", "info"}, messages(t, &stdout))
+	assert.Equal(t, []string{"warn", "error"}, messages(t, &stderr))
+}
+
+func TestErrorOutputToStderr_Disabled(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "info"}, &stdout, &stderr)
+
+	logger.Info("info")
+	logger.Error("error")
+
+	assert.Equal(t, []string{"info", "error"}, messages(t, &stdout))
+	assert.Empty(t, stderr.String())
+}
+
+func TestErrorOutputToStderr_KeepsAttributesAndContext(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "info", ErrorOutputToStderr: true}, &stdout, &stderr).
+		With("component", "auth")
+
+	logger.ErrorContext(WithRequestID(context.Background(), "req-1"), "failed")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(stderr.Bytes(), &entry))
+	assert.Equal(t, "auth", entry["component"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Empty(t, stdout.String())
+}
+
+func TestErrorOutputToStderr_RespectsLevel(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "error", ErrorOutputToStderr: true}, &stdout, &stderr)
+
+	logger.Info("info")
+	logger.Warn("warn")
+
+	assert.False(t, logger.Enabled(context.Background(), slog.LevelWarn))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestDualOutput_WritesJSONAndText(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "info", DualOutput: true}, &stdout, &stderr)
+	ctx := WithEmail(WithRequestID(context.Background(), "req-1"), "john.doe@example.com")
+
+	logger.InfoContext(ctx, "user logged in", "method", "Login")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &entry))
+	assert.Equal(t, "user logged in", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "j***@example.com", entry["email"])
+
+	text := stderr.String()
+	assert.False(t, json.Valid(stderr.Bytes()), "the human stream must not be JSON")
+	assert.Contains(t, text, `msg="user logged in"`)
+	assert.Contains(t, text, "request_id=req-1")
+	assert.Contains(t, text, "email=j***@example.com")
+	assert.Contains(t, text, "method=Login")
+}
+
+func TestDualOutput_Disabled(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := createLoggerWithStreams(config.LogConfig{Level: "info"}, &stdout, &stderr)
+
+	logger.Info("hello")
+
+	assert.Equal(t, []string{"hello"}, messages(t, &stdout))
+	assert.Empty(t, stderr.String())
+}
@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	// webhookQueueSize bounds the records waiting to be forwarded, newer records are dropped when full
+	webhookQueueSize = 100
+	// webhookMaxAttempts is how many times a record is posted before it is given up on
+	webhookMaxAttempts = 3
+	// webhookBackoff is the delay before the first retry, doubled for each further retry
+	webhookBackoff = 500 * time.Millisecond
+	// webhookRequestTimeout bounds a single POST
+	webhookRequestTimeout = 5 * time.Second
+)
+
+// webhookPayload is the JSON document posted for every forwarded record
+type webhookPayload struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Error   string         `json:"error,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// errorWebhook posts payloads to an external error tracker from a background goroutine
+type errorWebhook struct {
+	url     string
+	client  *http.Client
+	queue   chan webhookPayload
+	backoff time.Duration
+}
+
+// newErrorWebhook starts a forwarder posting to url
+func newErrorWebhook(url string) *errorWebhook {
+	w := &errorWebhook{
+		url:     url,
+		client:  &http.Client{Timeout: webhookRequestTimeout},
+		queue:   make(chan webhookPayload, webhookQueueSize),
+		backoff: webhookBackoff,
+	}
+	go w.run()
+	return w
+}
+
+// enqueue schedules payload for delivery without blocking, it reports false when the queue is full
+func (w *errorWebhook) enqueue(payload webhookPayload) bool {
+	select {
+	case w.queue <- payload:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *errorWebhook) run() {
+	for payload := range w.queue {
+		if err := w.deliver(payload); err != nil {
+			// WARN is never forwarded, so this cannot feed back into the queue
+			slog.Warn("Failed to forward error record", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// deliver posts payload, retrying with exponential backoff
+func (w *errorWebhook) deliver(payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode error record: %w", err)
+	}
+
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		err = w.post(body)
+		if err == nil || attempt == webhookMaxAttempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *errorWebhook) post(body []byte) error {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookHandler passes every record to next and forwards ERROR records to webhook
+// Forwarded fields are masked like the JSON output, nested groups are flattened to dotted keys
+type webhookHandler struct {
+	next    slog.Handler
+	webhook *errorWebhook
+	// attrs are the With attributes, already prefixed with their group
+	attrs  []slog.Attr
+	prefix string
+}
+
+func newWebhookHandler(next slog.Handler, webhook *errorWebhook) *webhookHandler {
+	return &webhookHandler{next: next, webhook: webhook}
+}
+
+func (h *webhookHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *webhookHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.next.Handle(ctx, r)
+	if r.Level >= slog.LevelError {
+		h.webhook.enqueue(h.payload(r))
+	}
+	return err
+}
+
+func (h *webhookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &clone
+}
+
+func (h *webhookHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// payload converts r into the forwarded document
+func (h *webhookHandler) payload(r slog.Record) webhookPayload {
+	fields := make(map[string]any)
+	for _, a := range h.attrs {
+		addField(fields, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addField(fields, h.prefix, a)
+		return true
+	})
+
+	payload := webhookPayload{
+		Time:    r.Time,
+		Level:   r.Level.String(),
+		Message: r.Message,
+		Fields:  fields,
+	}
+	if errValue, ok := fields["error"].(string); ok {
+		payload.Error = errValue
+		delete(fields, "error")
+	}
+	return payload
+}
+
+// addField stores the masked value of a under prefix+key, group values are flattened
+func addField(fields map[string]any, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, nested := range value.Group() {
+			addField(fields, groupPrefix, nested)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+
+	if err, ok := value.Any().(error); ok {
+		value = slog.StringValue(err.Error())
+	}
+	fields[prefix+a.Key] = maskValue(a.Key, value).Any()
+}
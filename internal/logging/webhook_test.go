@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookServer starts a stub error tracker that records every posted payload
+// Responses use the given status codes in order, then 200
+func webhookServer(t *testing.T, statuses ...int) (*httptest.Server, <-chan webhookPayload) {
+	t.Helper()
+	received := make(chan webhookPayload, 10)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload webhookPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		received <- payload
+
+		if call := int(calls.Add(1)); call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+// nextPayload waits for the next payload posted to the stub server
+func nextPayload(t *testing.T, received <-chan webhookPayload) webhookPayload {
+	t.Helper()
+	select {
+	case payload := <-received:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the error record to be posted")
+		return webhookPayload{}
+	}
+}
+
+func TestErrorWebhook_ForwardsErrorRecords(t *testing.T) {
+	srv, received := webhookServer(t)
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "info", ErrorWebhookURL: srv.URL}, &buf).With("component", "auth")
+	ctx := WithEmail(WithRequestID(context.Background(), "req-1"), "john.doe@example.com")
+
+	logger.InfoContext(ctx, "not forwarded")
+	logger.ErrorContext(ctx, "Failed to create user", "error", errors.New("db down"), "password", "secret")
+
+	payload := nextPayload(t, received)
+	assert.Equal(t, "ERROR", payload.Level)
+	assert.Equal(t, "Failed to create user", payload.Message)
+	assert.Equal(t, "db down", payload.Error)
+	assert.Equal(t, "req-1", payload.Fields["request_id"])
+	assert.Equal(t, "j***@example.com", payload.Fields["email"])
+	assert.Equal(t, "auth", payload.Fields["component"])
+	assert.Equal(t, utils.DefaultRedactionPlaceholder, payload.Fields["password"])
+	assert.NotContains(t, payload.Fields, "error")
+	assert.Contains(t, buf.String(), "not forwarded")
+}
+
+func TestErrorWebhook_IgnoresLowerLevels(t *testing.T) {
+	srv, received := webhookServer(t)
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "debug", ErrorWebhookURL: srv.URL}, &buf)
+
+	logger.Info("info")
+	logger.Warn("warn")
+
+	select {
+	case payload := <-received:
+		t.Fatalf("unexpected POST for %q", payload.Message)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestErrorWebhook_FlattensGroups(t *testing.T) {
+	srv, received := webhookServer(t)
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "info", ErrorWebhookURL: srv.URL}, &buf).WithGroup("request").With("method", "Login")
+
+	logger.Error("failed", "attempt", 2)
+
+	payload := nextPayload(t, received)
+	assert.Equal(t, "Login", payload.Fields["request.method"])
+	assert.Equal(t, float64(2), payload.Fields["request.attempt"])
+}
+
+func TestErrorWebhook_AppliesFieldAllowlist(t *testing.T) {
+	srv, received := webhookServer(t)
+	var buf bytes.Buffer
+	logger := createLogger(config.LogConfig{Level: "info", ErrorWebhookURL: srv.URL, FieldAllowlist: []string{"request_id", "error"}}, &buf)
+	ctx := WithEmail(WithRequestID(context.Background(), "req-1"), "john.doe@example.com")
+
+	logger.ErrorContext(ctx, "failed", "error", errors.New("db down"), "user_id", "42")
+
+	payload := nextPayload(t, received)
+	assert.Equal(t, "db down", payload.Error)
+	assert.Equal(t, map[string]any{"request_id": "req-1"}, payload.Fields)
+}
+
+func TestErrorWebhook_RetriesFailedPosts(t *testing.T) {
+	srv, received := webhookServer(t, http.StatusServiceUnavailable)
+	webhook := newErrorWebhook(srv.URL)
+	webhook.backoff = time.Millisecond
+
+	require.True(t, webhook.enqueue(webhookPayload{Level: "ERROR", Message: "failed"}))
+
+	assert.Equal(t, "failed", nextPayload(t, received).Message)
+	assert.Equal(t, "failed", nextPayload(t, received).Message)
+}
+
+func TestErrorWebhook_FullQueueDoesNotBlock(t *testing.T) {
+	webhook := &errorWebhook{queue: make(chan webhookPayload, 1)}
+
+	assert.True(t, webhook.enqueue(webhookPayload{Message: "first"}))
+	assert.False(t, webhook.enqueue(webhookPayload{Message: "second"}))
+}
@@ -9,8 +9,12 @@ import (
 
 //go:generate mockery --name=IMessageBroker --output=./mocks --outpkg=mocks --filename=IMessageBroker.go
 type IMessageBroker interface {
-	PublishUserCreated(user *models.User) error
-	PublishUserDeleted(user *models.User) error
+	PublishUserCreated(ctx context.Context, user *models.User) error
+	PublishUserDeleted(ctx context.Context, user *models.User) error
+	PublishUserSessionsRevoked(ctx context.Context, user *models.User) error
+	PublishUserSuspended(ctx context.Context, user *models.User) error
+	PublishUserUnsuspended(ctx context.Context, user *models.User) error
+	Drain(ctx context.Context) error
 	Close()
 }
 
@@ -23,6 +27,7 @@ type IRabbitMQConn interface {
 type IRabbitMQPublisher interface {
 	Publish(data []byte, routingKeys []string, optionFuncs ...func(*rabbitmq.PublishOptions)) error
 	PublishWithContext(ctx context.Context, data []byte, routingKeys []string, optionFuncs ...func(*rabbitmq.PublishOptions)) error
+	PublishWithDeferredConfirmWithContext(ctx context.Context, data []byte, routingKeys []string, optionFuncs ...func(*rabbitmq.PublishOptions)) (rabbitmq.PublisherConfirmation, error)
 	Close()
 	NotifyPublish(handler func(p rabbitmq.Confirmation))
 	NotifyReturn(handler func(r rabbitmq.Return))
@@ -3,8 +3,11 @@
 package mocks
 
 import (
-	models "github.com/Koshsky/subs-service/auth-service/internal/models"
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/Koshsky/subs-service/auth-service/internal/models"
 )
 
 // IMessageBroker is an autogenerated mock type for the IMessageBroker type
@@ -17,17 +20,35 @@ func (_m *IMessageBroker) Close() {
 	_m.Called()
 }
 
-// PublishUserCreated provides a mock function with given fields: user
-func (_m *IMessageBroker) PublishUserCreated(user *models.User) error {
-	ret := _m.Called(user)
+// Drain provides a mock function with given fields: ctx
+func (_m *IMessageBroker) Drain(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Drain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserCreated provides a mock function with given fields: ctx, user
+func (_m *IMessageBroker) PublishUserCreated(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PublishUserCreated")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -35,17 +56,71 @@ func (_m *IMessageBroker) PublishUserCreated(user *models.User) error {
 	return r0
 }
 
-// PublishUserDeleted provides a mock function with given fields: user
-func (_m *IMessageBroker) PublishUserDeleted(user *models.User) error {
-	ret := _m.Called(user)
+// PublishUserDeleted provides a mock function with given fields: ctx, user
+func (_m *IMessageBroker) PublishUserDeleted(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PublishUserDeleted")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserSessionsRevoked provides a mock function with given fields: ctx, user
+func (_m *IMessageBroker) PublishUserSessionsRevoked(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserSessionsRevoked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserSuspended provides a mock function with given fields: ctx, user
+func (_m *IMessageBroker) PublishUserSuspended(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserSuspended")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishUserUnsuspended provides a mock function with given fields: ctx, user
+func (_m *IMessageBroker) PublishUserUnsuspended(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishUserUnsuspended")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
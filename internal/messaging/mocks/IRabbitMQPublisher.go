@@ -80,6 +80,43 @@ func (_m *IRabbitMQPublisher) PublishWithContext(ctx context.Context, data []byt
 	return r0
 }
 
+// PublishWithDeferredConfirmWithContext provides a mock function with given fields: ctx, data, routingKeys, optionFuncs
+func (_m *IRabbitMQPublisher) PublishWithDeferredConfirmWithContext(ctx context.Context, data []byte, routingKeys []string, optionFuncs ...func(*rabbitmq.PublishOptions)) (rabbitmq.PublisherConfirmation, error) {
+	_va := make([]interface{}, len(optionFuncs))
+	for _i := range optionFuncs {
+		_va[_i] = optionFuncs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, data, routingKeys)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishWithDeferredConfirmWithContext")
+	}
+
+	var r0 rabbitmq.PublisherConfirmation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []string, ...func(*rabbitmq.PublishOptions)) (rabbitmq.PublisherConfirmation, error)); ok {
+		return rf(ctx, data, routingKeys, optionFuncs...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []string, ...func(*rabbitmq.PublishOptions)) rabbitmq.PublisherConfirmation); ok {
+		r0 = rf(ctx, data, routingKeys, optionFuncs...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(rabbitmq.PublisherConfirmation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []string, ...func(*rabbitmq.PublishOptions)) error); ok {
+		r1 = rf(ctx, data, routingKeys, optionFuncs...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIRabbitMQPublisher creates a new instance of IRabbitMQPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIRabbitMQPublisher(t interface {
@@ -0,0 +1,176 @@
+package messaging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// OutboxEntry is an event that could not be published and waits for replay
+type OutboxEntry struct {
+	EventID    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	RoutingKey string `json:"routing_key"`
+	Body       []byte `json:"body"`
+}
+
+// Outbox is a file-backed FIFO queue of unpublished events stored as JSON lines
+// Entries survive restarts and are replayed in the order they were appended
+type Outbox struct {
+	path    string
+	mu      sync.Mutex
+	entries []OutboxEntry
+
+	// replayMu allows one replay at a time, entries are only removed by the replay holding it
+	replayMu sync.Mutex
+}
+
+// NewOutbox opens the outbox at path, loading entries left from a previous run
+func NewOutbox(path string) (*Outbox, error) {
+	entries, err := readOutboxFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Outbox{path: path, entries: entries}, nil
+}
+
+// Append durably adds an entry to the end of the outbox
+func (o *Outbox) Append(entry OutboxEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %v", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	file, err := os.OpenFile(o.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %v", err)
+	}
+
+	o.entries = append(o.entries, entry)
+	return nil
+}
+
+// Len returns the number of entries waiting for replay
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Replay publishes entries in order until one fails or ctx is done
+// It publishes from a snapshot so appends are not blocked by a slow broker, entries appended meanwhile stay queued behind it
+// Published entries are removed from memory before the file is rewritten, so a failed rewrite never publishes them twice
+// in this process, the returned count is the number of published entries even when the rewrite fails
+func (o *Outbox) Replay(ctx context.Context, publish func(OutboxEntry) error) (int, error) {
+	o.replayMu.Lock()
+	defer o.replayMu.Unlock()
+
+	o.mu.Lock()
+	pending := append([]OutboxEntry(nil), o.entries...)
+	o.mu.Unlock()
+
+	replayed := 0
+	var publishErr error
+	for _, entry := range pending {
+		if err := ctx.Err(); err != nil {
+			publishErr = err
+			break
+		}
+		if err := publish(entry); err != nil {
+			publishErr = err
+			break
+		}
+		replayed++
+	}
+	if replayed == 0 {
+		return 0, publishErr
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append([]OutboxEntry(nil), o.entries[replayed:]...)
+	if err := writeOutboxFile(o.path, o.entries); err != nil {
+		return replayed, errors.Join(publishErr, err)
+	}
+	return replayed, publishErr
+}
+
+// readOutboxFile loads all entries from path, a missing file is an empty outbox
+func readOutboxFile(path string) ([]OutboxEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %v", err)
+	}
+	defer file.Close()
+
+	var entries []OutboxEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry OutboxEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse outbox entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %v", err)
+	}
+	return entries, nil
+}
+
+// writeOutboxFile atomically replaces the outbox file with entries
+func writeOutboxFile(path string, entries []OutboxEntry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to rewrite outbox: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to marshal outbox entry: %v", err)
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to rewrite outbox: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync outbox: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to rewrite outbox: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace outbox: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func outboxEntry(id string) OutboxEntry {
+	return OutboxEntry{EventID: id, EventType: EventUserCreated, RoutingKey: EventUserCreated, Body: []byte(`{"id":"` + id + `"}`)}
+}
+
+func TestOutbox_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outbox, err := NewOutbox(path)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Append(outboxEntry("1")))
+	require.NoError(t, outbox.Append(outboxEntry("2")))
+
+	reopened, err := NewOutbox(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+	assert.Equal(t, []OutboxEntry{outboxEntry("1"), outboxEntry("2")}, reopened.entries)
+}
+
+func TestOutbox_ReplayInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outbox, err := NewOutbox(path)
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, outbox.Append(outboxEntry(id)))
+	}
+
+	var published []string
+	replayed, err := outbox.Replay(context.Background(), func(e OutboxEntry) error {
+		published = append(published, e.EventID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, replayed)
+	assert.Equal(t, []string{"1", "2", "3"}, published)
+	assert.Equal(t, 0, outbox.Len())
+	reopened, err := NewOutbox(path)
+	require.NoError(t, err)
+	assert.Equal(t, 0, reopened.Len())
+}
+
+func TestOutbox_ReplayStopsAtFirstFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outbox, err := NewOutbox(path)
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, outbox.Append(outboxEntry(id)))
+	}
+
+	replayed, err := outbox.Replay(context.Background(), func(e OutboxEntry) error {
+		if e.EventID == "2" {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, replayed)
+	reopened, err := NewOutbox(path)
+	require.NoError(t, err)
+	assert.Equal(t, []OutboxEntry{outboxEntry("2"), outboxEntry("3")}, reopened.entries)
+}
+
+func TestOutbox_ReplayRespectsCancelledContext(t *testing.T) {
+	outbox, err := NewOutbox(filepath.Join(t.TempDir(), "outbox.jsonl"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.Append(outboxEntry("1")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	replayed, err := outbox.Replay(ctx, func(OutboxEntry) error {
+		t.Fatal("publish must not be called after cancellation")
+		return nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, replayed)
+	assert.Equal(t, 1, outbox.Len())
+}
+
+func TestOutbox_ReplayDoesNotBlockAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outbox, err := NewOutbox(path)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Append(outboxEntry("1")))
+
+	replayed, err := outbox.Replay(context.Background(), func(e OutboxEntry) error {
+		return outbox.Append(outboxEntry("2"))
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	reopened, err := NewOutbox(path)
+	require.NoError(t, err)
+	assert.Equal(t, []OutboxEntry{outboxEntry("2")}, reopened.entries)
+}
+
+func TestOutbox_ReplayRewriteFailureDoesNotRepublish(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	outbox, err := NewOutbox(filepath.Join(dir, "outbox.jsonl"))
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, outbox.Append(outboxEntry(id)))
+	}
+	require.NoError(t, os.RemoveAll(dir))
+
+	var published []string
+	publish := func(e OutboxEntry) error {
+		published = append(published, e.EventID)
+		return nil
+	}
+	replayed, err := outbox.Replay(context.Background(), publish)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to rewrite outbox")
+	assert.Equal(t, 2, replayed)
+	assert.Equal(t, 0, outbox.Len())
+	replayed, err = outbox.Replay(context.Background(), publish)
+	require.NoError(t, err)
+	assert.Equal(t, 0, replayed)
+	assert.Equal(t, []string{"1", "2"}, published)
+}
@@ -1,34 +1,173 @@
 package messaging
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"github.com/google/uuid"
 	"github.com/wagslane/go-rabbitmq"
 )
 
+// drainPollInterval is how often Drain checks for outstanding confirmations
+const drainPollInterval = 10 * time.Millisecond
+
+// defaultOutboxReplayInterval is used when no outbox replay interval is configured
+const defaultOutboxReplayInterval = 5 * time.Second
+
 // RabbitMQAdapter implements IMessageBroker for RabbitMQ
 type RabbitMQAdapter struct {
+	// connMu guards publisher and conn, which are attached later when the broker was unreachable at startup
+	connMu    sync.RWMutex
 	publisher IRabbitMQPublisher
 	conn      IRabbitMQConn
 	config    config.RabbitMQConfig
+
+	// connect dials the broker for an adapter that started disconnected, see NewBufferingRabbitMQAdapter
+	connect func() (IRabbitMQConn, IRabbitMQPublisher, error)
+
+	// confirms is set when the publisher runs in confirm mode, pending counts publishes still waiting for confirmation
+	confirms  bool
+	pendingMu sync.Mutex
+	pending   int
+
+	// outbox buffers events that failed to publish, nil when disabled
+	outbox     *Outbox
+	stopReplay context.CancelFunc
+	replayDone chan struct{}
 }
 
+const (
+	EventUserCreated = "user.created"
+	EventUserDeleted = "user.deleted"
+
+	EventUserSessionsRevoked = "user.sessions_revoked"
+	EventUserSuspended       = "user.suspended"
+	EventUserUnsuspended     = "user.unsuspended"
+)
+
+var (
+	// ErrPublishCancelled is returned when the caller's context ended before the publish completed
+	// It wraps the context error
+	ErrPublishCancelled = errors.New("publish cancelled")
+	// ErrBrokerUnavailable is returned when the broker could not accept the event
+	ErrBrokerUnavailable = errors.New("message broker unavailable")
+	// ErrPayloadTooLarge is returned when an event body exceeds the configured maximum payload size
+	ErrPayloadTooLarge = errors.New("event payload too large")
+
+	// errNotConnected is the cause of ErrBrokerUnavailable while the broker has not been reached yet
+	errNotConnected = errors.New("not connected to RabbitMQ")
+)
+
+// Event fields holding personal data carry mask tags, see loggedPayload
 type UserCreatedEvent struct {
 	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	Email  string    `json:"email" mask:"email"`
 }
 
 type UserDeletedEvent struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+type UserSessionsRevokedEvent struct {
+	UserID    uuid.UUID `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type UserSuspendedEvent struct {
+	UserID      uuid.UUID `json:"user_id"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+type UserUnsuspendedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// eventPayloads creates an empty value of each event type, used to decode published bodies for logging
+var eventPayloads = map[string]func() any{
+	EventUserCreated:         func() any { return &UserCreatedEvent{} },
+	EventUserDeleted:         func() any { return &UserDeletedEvent{} },
+	EventUserSessionsRevoked: func() any { return &UserSessionsRevokedEvent{} },
+	EventUserSuspended:       func() any { return &UserSuspendedEvent{} },
+	EventUserUnsuspended:     func() any { return &UserUnsuspendedEvent{} },
+}
+
+// loggedPayload returns a masked copy of an event body for logs, the published body is left untouched
+// It returns nil for unknown event types and bodies that do not decode
+func loggedPayload(eventType string, body []byte) map[string]any {
+	newPayload, ok := eventPayloads[eventType]
+	if !ok {
+		return nil
+	}
+	payload := newPayload()
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil
+	}
+	return utils.MaskStruct(payload)
+}
+
 // NewRabbitMQAdapter creates a new RabbitMQ adapter
 func NewRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, error) {
+	conn, publisher, err := dialRabbitMQ(rabbitmqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := &RabbitMQAdapter{
+		config:   rabbitmqConfig,
+		confirms: true,
+	}
+	adapter.attach(conn, publisher)
+
+	if rabbitmqConfig.OutboxPath != "" {
+		outbox, err := NewOutbox(rabbitmqConfig.OutboxPath)
+		if err != nil {
+			adapter.Close()
+			return nil, fmt.Errorf("failed to open outbox: %v", err)
+		}
+		adapter.outbox = outbox
+		adapter.startOutboxReplay(rabbitmqConfig.OutboxReplayInterval)
+	}
+
+	return adapter, nil
+}
+
+// NewBufferingRabbitMQAdapter creates an adapter for a broker that is unreachable at startup
+// Events are buffered in the outbox, which must be configured, while the replay worker keeps dialing the broker,
+// and are replayed once it connects
+func NewBufferingRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, error) {
+	if rabbitmqConfig.OutboxPath == "" {
+		return nil, errors.New("an outbox is required to buffer events until RabbitMQ is reachable")
+	}
+
+	outbox, err := NewOutbox(rabbitmqConfig.OutboxPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %v", err)
+	}
+
+	adapter := &RabbitMQAdapter{
+		config:   rabbitmqConfig,
+		confirms: true,
+		outbox:   outbox,
+		connect: func() (IRabbitMQConn, IRabbitMQPublisher, error) {
+			return dialRabbitMQ(rabbitmqConfig)
+		},
+	}
+	adapter.startOutboxReplay(rabbitmqConfig.OutboxReplayInterval)
+
+	return adapter, nil
+}
+
+// dialRabbitMQ opens a connection and a confirm mode publisher, both reconnect automatically once established
+func dialRabbitMQ(rabbitmqConfig config.RabbitMQConfig) (IRabbitMQConn, IRabbitMQPublisher, error) {
 	// Create connection with automatic reconnection
 	conn, err := rabbitmq.NewConn(
 		rabbitmqConfig.URL,
@@ -36,7 +175,7 @@ func NewRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, e
 		rabbitmq.WithConnectionOptionsReconnectInterval(5), // 5 seconds between reconnection attempts
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+		return nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
 	}
 
 	// Create publisher with automatic reconnection
@@ -47,22 +186,43 @@ func NewRabbitMQAdapter(rabbitmqConfig config.RabbitMQConfig) (IMessageBroker, e
 		rabbitmq.WithPublisherOptionsExchangeDeclare,
 		rabbitmq.WithPublisherOptionsExchangeKind("topic"),
 		rabbitmq.WithPublisherOptionsExchangeDurable,
+		rabbitmq.WithPublisherOptionsConfirm,
 	)
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to create publisher: %v", err)
+		return nil, nil, fmt.Errorf("failed to create publisher: %v", err)
 	}
 
-	return &RabbitMQAdapter{
-		publisher: publisher,
-		conn:      conn,
-		config:    rabbitmqConfig,
-	}, nil
+	return conn, publisher, nil
+}
+
+// attach installs a connected publisher, registering the confirmation handler in confirm mode
+func (r *RabbitMQAdapter) attach(conn IRabbitMQConn, publisher IRabbitMQPublisher) {
+	if r.confirms {
+		publisher.NotifyPublish(r.handleConfirmation)
+	}
+
+	r.connMu.Lock()
+	defer r.connMu.Unlock()
+	r.conn = conn
+	r.publisher = publisher
+}
+
+// currentPublisher returns the attached publisher, nil while the broker has not been reached yet
+func (r *RabbitMQAdapter) currentPublisher() IRabbitMQPublisher {
+	r.connMu.RLock()
+	defer r.connMu.RUnlock()
+	return r.publisher
+}
+
+// canPublish reports whether events can be published now or buffered until the broker is reachable
+func (r *RabbitMQAdapter) canPublish() bool {
+	return r.currentPublisher() != nil || r.outbox != nil
 }
 
 // PublishUserCreated publishes user created event to RabbitMQ
-func (r *RabbitMQAdapter) PublishUserCreated(user *models.User) error {
-	if r.publisher == nil {
+func (r *RabbitMQAdapter) PublishUserCreated(ctx context.Context, user *models.User) error {
+	if !r.canPublish() {
 		return errors.New("publisher is not initialized")
 	}
 
@@ -80,21 +240,16 @@ func (r *RabbitMQAdapter) PublishUserCreated(user *models.User) error {
 		return fmt.Errorf("failed to marshal user created event: %v", err)
 	}
 
-	err = r.publisher.Publish(
-		body,
-		[]string{"user.created"},
-		rabbitmq.WithPublishOptionsContentType("application/json"),
-		rabbitmq.WithPublishOptionsExchange(r.config.Exchange),
-	)
+	err = r.publish(ctx, EventUserCreated, body)
 	if err != nil {
-		return fmt.Errorf("failed to publish user created event: %v", err)
+		return fmt.Errorf("failed to publish user created event: %w", err)
 	}
 
 	return nil
 }
 
-func (r *RabbitMQAdapter) PublishUserDeleted(user *models.User) error {
-	if r.publisher == nil {
+func (r *RabbitMQAdapter) PublishUserDeleted(ctx context.Context, user *models.User) error {
+	if !r.canPublish() {
 		return errors.New("publisher is not initialized")
 	}
 
@@ -111,21 +266,370 @@ func (r *RabbitMQAdapter) PublishUserDeleted(user *models.User) error {
 		return fmt.Errorf("failed to marshal user deleted event: %v", err)
 	}
 
-	err = r.publisher.Publish(
-		body,
-		[]string{"user.deleted"},
-		rabbitmq.WithPublishOptionsContentType("application/json"),
+	err = r.publish(ctx, EventUserDeleted, body)
+	if err != nil {
+		return fmt.Errorf("failed to publish user deleted event: %w", err)
+	}
+
+	return nil
+}
+
+// PublishUserSessionsRevoked publishes the event emitted when all sessions of a user are revoked
+func (r *RabbitMQAdapter) PublishUserSessionsRevoked(ctx context.Context, user *models.User) error {
+	if !r.canPublish() {
+		return errors.New("publisher is not initialized")
+	}
+
+	if user == nil || user.SessionsRevokedAt == nil {
+		return errors.New("user with revoked sessions is required")
+	}
+
+	event := UserSessionsRevokedEvent{
+		UserID:    user.ID,
+		RevokedAt: *user.SessionsRevokedAt,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user sessions revoked event: %v", err)
+	}
+
+	err = r.publish(ctx, EventUserSessionsRevoked, body)
+	if err != nil {
+		return fmt.Errorf("failed to publish user sessions revoked event: %w", err)
+	}
+
+	return nil
+}
+
+// PublishUserSuspended publishes the event emitted when a user's account is suspended
+func (r *RabbitMQAdapter) PublishUserSuspended(ctx context.Context, user *models.User) error {
+	if !r.canPublish() {
+		return errors.New("publisher is not initialized")
+	}
+
+	if user == nil || user.SuspendedAt == nil {
+		return errors.New("suspended user is required")
+	}
+
+	event := UserSuspendedEvent{
+		UserID:      user.ID,
+		SuspendedAt: *user.SuspendedAt,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user suspended event: %v", err)
+	}
+
+	err = r.publish(ctx, EventUserSuspended, body)
+	if err != nil {
+		return fmt.Errorf("failed to publish user suspended event: %w", err)
+	}
+
+	return nil
+}
+
+// PublishUserUnsuspended publishes the event emitted when a user's suspension is lifted
+func (r *RabbitMQAdapter) PublishUserUnsuspended(ctx context.Context, user *models.User) error {
+	if !r.canPublish() {
+		return errors.New("publisher is not initialized")
+	}
+
+	if user == nil {
+		return errors.New("user cannot be nil")
+	}
+
+	event := UserUnsuspendedEvent{
+		UserID: user.ID,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user unsuspended event: %v", err)
+	}
+
+	err = r.publish(ctx, EventUserUnsuspended, body)
+	if err != nil {
+		return fmt.Errorf("failed to publish user unsuspended event: %w", err)
+	}
+
+	return nil
+}
+
+// publish sends an event body with a fresh message id to the routing key of its event type
+// When the outbox is enabled, failed events and events published before the broker is reached are buffered
+// for replay instead of returned as errors, and new events queue behind buffered ones to keep ordering
+// Bodies above the configured maximum payload size are rejected before reaching the channel or the outbox
+func (r *RabbitMQAdapter) publish(ctx context.Context, eventType string, body []byte) error {
+	if limit := r.config.MaxPayloadBytes; limit > 0 && len(body) > limit {
+		return fmt.Errorf("%w: %s event is %d bytes, limit is %d", ErrPayloadTooLarge, eventType, len(body), limit)
+	}
+
+	entry := OutboxEntry{
+		EventID:    utils.NewID(),
+		EventType:  eventType,
+		RoutingKey: r.routingKey(eventType),
+		Body:       body,
+	}
+
+	if r.outbox != nil && (r.outbox.Len() > 0 || r.currentPublisher() == nil) {
+		return r.bufferEvent(ctx, entry)
+	}
+
+	err := r.send(ctx, entry)
+	if err != nil && r.outbox != nil {
+		return r.bufferEvent(ctx, entry)
+	}
+	return err
+}
+
+// routingKey returns the configured routing key for eventType, falling back to the event type itself
+func (r *RabbitMQAdapter) routingKey(eventType string) string {
+	if key := r.config.RoutingKeys[eventType]; key != "" {
+		return key
+	}
+	return eventType
+}
+
+// contentType returns the configured content type for eventType, falling back to config.DefaultContentType
+func (r *RabbitMQAdapter) contentType(eventType string) string {
+	if contentType := r.config.ContentTypes[eventType]; contentType != "" {
+		return contentType
+	}
+	return config.DefaultContentType
+}
+
+// deliveryMode sets persistent delivery on published events unless transient delivery is configured
+func (r *RabbitMQAdapter) deliveryMode(options *rabbitmq.PublishOptions) {
+	if r.config.TransientDelivery {
+		options.DeliveryMode = rabbitmq.Transient
+		return
+	}
+	options.DeliveryMode = rabbitmq.Persistent
+}
+
+// send publishes a single event and logs the outcome
+// with event_type, event_id, routing_key and exchange attributes and the masked payload
+// The publish, and the broker confirmation in confirm mode, are bounded by ctx and the publish timeout
+// Failures wrap ErrPublishCancelled when ctx ended and ErrBrokerUnavailable otherwise
+func (r *RabbitMQAdapter) send(ctx context.Context, entry OutboxEntry) error {
+	attrs := eventAttrs(entry, r.config.Exchange)
+	if payload := loggedPayload(entry.EventType, entry.Body); payload != nil {
+		attrs = append(attrs, slog.Any("payload", payload))
+	}
+
+	publishCtx, cancel := r.publishContext(ctx)
+	defer cancel()
+
+	options := []func(*rabbitmq.PublishOptions){
+		rabbitmq.WithPublishOptionsContentType(r.contentType(entry.EventType)),
+		r.deliveryMode,
 		rabbitmq.WithPublishOptionsExchange(r.config.Exchange),
-	)
+		rabbitmq.WithPublishOptionsMessageID(entry.EventID),
+	}
+
+	var err error
+	publisher := r.currentPublisher()
+	switch {
+	case publisher == nil:
+		err = errNotConnected
+	case r.confirms:
+		err = r.publishConfirmed(publishCtx, publisher, entry, options)
+	default:
+		err = publisher.PublishWithContext(publishCtx, entry.Body, []string{entry.RoutingKey}, options...)
+	}
+	if err != nil {
+		err = r.publishError(ctx, err)
+		// A timed out or cancelled publish says nothing about the connection itself
+		if errors.Is(err, ErrBrokerUnavailable) && !errors.Is(err, context.DeadlineExceeded) {
+			metrics.BrokerConnected.Set(0)
+		}
+		slog.ErrorContext(ctx, "Failed to publish event", append(attrs, slog.String("error", err.Error()))...)
+		return err
+	}
+	metrics.BrokerConnected.Set(1)
+
+	slog.InfoContext(ctx, "Event published", attrs...)
+	return nil
+}
+
+// publishContext returns the context bounding a single publish, only ctx bounds it when no timeout is configured
+func (r *RabbitMQAdapter) publishContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.config.PublishTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.config.PublishTimeout)
+}
+
+// publishError classifies a failed publish as cancelled by the caller or as the broker being unavailable
+func (r *RabbitMQAdapter) publishError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ErrPublishCancelled, ctxErr)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: publish timed out after %s: %w", ErrBrokerUnavailable, r.config.PublishTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrBrokerUnavailable, err)
+}
+
+// publishConfirmed publishes an event in confirm mode and waits for the broker to settle it
+// The event counts as pending until waiting ends, so a confirmation lost to a reconnect cannot keep it pending
+func (r *RabbitMQAdapter) publishConfirmed(ctx context.Context, publisher IRabbitMQPublisher, entry OutboxEntry, options []func(*rabbitmq.PublishOptions)) error {
+	r.pendingMu.Lock()
+	r.pending++
+	r.pendingMu.Unlock()
+	defer func() {
+		r.pendingMu.Lock()
+		r.pending--
+		r.pendingMu.Unlock()
+	}()
+
+	confirmations, err := publisher.PublishWithDeferredConfirmWithContext(ctx, entry.Body, []string{entry.RoutingKey}, options...)
 	if err != nil {
-		return fmt.Errorf("failed to publish user deleted event: %v", err)
+		return err
 	}
 
+	for _, confirmation := range confirmations {
+		acked, err := confirmation.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !acked {
+			return errors.New("broker rejected published event")
+		}
+	}
+	return nil
+}
+
+// bufferEvent stores an unpublished event in the outbox
+func (r *RabbitMQAdapter) bufferEvent(ctx context.Context, entry OutboxEntry) error {
+	if err := r.outbox.Append(entry); err != nil {
+		return fmt.Errorf("failed to buffer event in outbox: %v", err)
+	}
+	slog.WarnContext(ctx, "Event buffered in outbox", eventAttrs(entry, r.config.Exchange)...)
 	return nil
 }
 
-// Close closes the RabbitMQ connection
+// eventAttrs returns the log attributes identifying an event
+func eventAttrs(entry OutboxEntry, exchange string) []any {
+	return []any{
+		slog.String("event_type", entry.EventType),
+		slog.String("event_id", entry.EventID),
+		slog.String("routing_key", entry.RoutingKey),
+		slog.String("exchange", exchange),
+	}
+}
+
+// startOutboxReplay starts a background worker that replays the outbox every interval
+// An adapter that started disconnected dials the broker on each tick until it connects
+func (r *RabbitMQAdapter) startOutboxReplay(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultOutboxReplayInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.stopReplay = cancel
+	r.replayDone = make(chan struct{})
+
+	go func() {
+		defer close(r.replayDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if r.ensureConnected(ctx) {
+					r.replayOutbox(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// ensureConnected dials the broker if no publisher is attached yet and reports whether one is attached
+func (r *RabbitMQAdapter) ensureConnected(ctx context.Context) bool {
+	if r.currentPublisher() != nil {
+		return true
+	}
+	if r.connect == nil {
+		return false
+	}
+
+	conn, publisher, err := r.connect()
+	if err != nil {
+		slog.WarnContext(ctx, "RabbitMQ is still unavailable, events stay in the outbox",
+			slog.Int("buffered", r.outbox.Len()), slog.String("error", err.Error()))
+		return false
+	}
+	r.attach(conn, publisher)
+	metrics.BrokerConnected.Set(1)
+	slog.InfoContext(ctx, "Connected to RabbitMQ", slog.Int("buffered", r.outbox.Len()))
+	return true
+}
+
+// replayOutbox publishes buffered events in order, stopping at the first failure
+func (r *RabbitMQAdapter) replayOutbox(ctx context.Context) {
+	if r.outbox.Len() == 0 {
+		return
+	}
+
+	replayed, err := r.outbox.Replay(ctx, func(entry OutboxEntry) error {
+		return r.send(ctx, entry)
+	})
+	if replayed > 0 {
+		slog.InfoContext(ctx, "Replayed outbox events", slog.Int("replayed", replayed), slog.Int("remaining", r.outbox.Len()))
+	}
+	if err != nil && ctx.Err() == nil {
+		slog.WarnContext(ctx, "Outbox replay stopped", slog.Int("remaining", r.outbox.Len()), slog.String("error", err.Error()))
+	}
+}
+
+// handleConfirmation logs events the broker rejected, publishConfirmed settles the pending count itself
+func (r *RabbitMQAdapter) handleConfirmation(c rabbitmq.Confirmation) {
+	if !c.Ack {
+		slog.Warn("Broker rejected published event", slog.Uint64("delivery_tag", c.DeliveryTag))
+	}
+}
+
+// pendingConfirmations returns the number of published events awaiting confirmation
+func (r *RabbitMQAdapter) pendingConfirmations() int {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	return r.pending
+}
+
+// Drain waits until no published event is waiting for its confirmation or ctx is done
+// Each wait is bounded by the caller context and the publish timeout, a lost confirmation only lasts until then
+func (r *RabbitMQAdapter) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := r.pendingConfirmations()
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to drain %d pending events: %w", pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops outbox replay and closes the RabbitMQ connection
 func (r *RabbitMQAdapter) Close() {
+	if r.stopReplay != nil {
+		r.stopReplay()
+		<-r.replayDone
+	}
+
+	r.connMu.RLock()
+	defer r.connMu.RUnlock()
 	if r.publisher != nil {
 		r.publisher.Close()
 	}
@@ -1,14 +1,27 @@
 package messaging
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/wagslane/go-rabbitmq"
 
 	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
 )
@@ -20,6 +33,8 @@ type RabbitMQAdapterTestSuite struct {
 	adapter       IMessageBroker
 	config        config.RabbitMQConfig
 	testUser      *models.User
+	logs          *bytes.Buffer
+	defaultLogger *slog.Logger
 }
 
 func (suite *RabbitMQAdapterTestSuite) SetupSuite() {
@@ -40,25 +55,56 @@ func (suite *RabbitMQAdapterTestSuite) SetupTest() {
 		conn:      suite.mockConn,
 		config:    suite.config,
 	}
+
+	suite.logs = &bytes.Buffer{}
+	suite.defaultLogger = slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(suite.logs, nil)))
 }
 
 func (suite *RabbitMQAdapterTestSuite) TearDownTest() {
+	slog.SetDefault(suite.defaultLogger)
 	suite.mockPublisher.AssertExpectations(suite.T())
 	suite.mockConn.AssertExpectations(suite.T())
 }
 
 // ===== MOCK HELPER FUNCTIONS =====
 
-// mockPublisherPublish mock publisher.Publish(data, routingKeys, options, options)
-func (suite *RabbitMQAdapterTestSuite) mockPublisherPublish(data []byte, routingKeys []string, err error) {
-	suite.mockPublisher.On("Publish",
+// mockPublisherPublish mock publisher.PublishWithContext(ctx, data, routingKeys, contentType, deliveryMode, exchange, messageID)
+func (suite *RabbitMQAdapterTestSuite) mockPublisherPublish(data any, routingKeys []string, err error) *mock.Call {
+	return suite.mockPublisher.On("PublishWithContext",
+		mock.Anything,
 		data,
 		routingKeys,
 		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
 		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
 	).Return(err)
 }
 
+// mockPublisherConfirm mock publisher.PublishWithDeferredConfirmWithContext returning the given confirmations
+func (suite *RabbitMQAdapterTestSuite) mockPublisherConfirm(data []byte, routingKeys []string, confirmations rabbitmq.PublisherConfirmation) *mock.Call {
+	return suite.mockPublisher.On("PublishWithDeferredConfirmWithContext",
+		mock.Anything,
+		data,
+		routingKeys,
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+		mock.AnythingOfType("func(*rabbitmq.PublishOptions)"),
+	).Return(confirmations, nil)
+}
+
+// lastLogEntry parses the last captured JSON log line
+func (suite *RabbitMQAdapterTestSuite) lastLogEntry() map[string]any {
+	lines := bytes.Split(bytes.TrimSpace(suite.logs.Bytes()), []byte("\n"))
+	suite.Require().NotEmpty(lines[len(lines)-1])
+
+	var entry map[string]any
+	suite.Require().NoError(json.Unmarshal(lines[len(lines)-1], &entry))
+	return entry
+}
+
 // mockClose mocks both publisher.Close() and conn.Close()
 func (suite *RabbitMQAdapterTestSuite) mockClose(err error) {
 	suite.mockPublisher.On("Close").Return()
@@ -93,13 +139,58 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_Success() {
 	suite.mockPublisherPublish(expectedData, expectedRoutingKeys, nil)
 
 	// Act
-	err := suite.adapter.PublishUserCreated(suite.testUser)
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().NoError(err)
 	suite.mockPublisher.AssertExpectations(suite.T())
 }
 
+// publishedOptions returns the publish options applied by the publisher call
+func publishedOptions(call mock.Arguments) rabbitmq.PublishOptions {
+	var options rabbitmq.PublishOptions
+	for _, arg := range call[3:] {
+		arg.(func(*rabbitmq.PublishOptions))(&options)
+	}
+	return options
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_PersistentByDefault() {
+	// Arrange
+	var options rabbitmq.PublishOptions
+	suite.mockPublisherPublish(mock.Anything, []string{"user.created"}, nil).Run(func(args mock.Arguments) {
+		options = publishedOptions(args)
+	})
+
+	// Act
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(uint8(amqp.Persistent), options.DeliveryMode)
+	suite.Equal(config.DefaultContentType, options.ContentType)
+	suite.Equal("test_exchange", options.Exchange)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_TransientAndContentTypeOverride() {
+	// Arrange
+	suite.config.TransientDelivery = true
+	suite.config.ContentTypes = map[string]string{EventUserCreated: "application/vnd.user-created+json"}
+	adapter := &RabbitMQAdapter{publisher: suite.mockPublisher, conn: suite.mockConn, config: suite.config}
+	var options rabbitmq.PublishOptions
+	suite.mockPublisherPublish(mock.Anything, []string{"user.created"}, nil).Run(func(args mock.Arguments) {
+		options = publishedOptions(args)
+	})
+
+	// Act
+	err := adapter.PublishUserCreated(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(uint8(amqp.Transient), options.DeliveryMode)
+	suite.Equal("application/vnd.user-created+json", options.ContentType)
+}
+
 func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_NilPublisher() {
 	// Arrange
 	adapter := &RabbitMQAdapter{
@@ -109,7 +200,7 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_NilPublisher() {
 	}
 
 	// Act
-	err := adapter.PublishUserCreated(suite.testUser)
+	err := adapter.PublishUserCreated(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -122,7 +213,7 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_PublisherError() {
 	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","email":"test@example.com"}`), []string{"user.created"}, expectedError)
 
 	// Act
-	err := suite.adapter.PublishUserCreated(suite.testUser)
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -135,13 +226,88 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_NilUser() {
 	var user *models.User = nil
 
 	// Act
-	err := suite.adapter.PublishUserCreated(user)
+	err := suite.adapter.PublishUserCreated(context.Background(), user)
 
 	// Assert
 	suite.Require().Error(err)
 	suite.Contains(err.Error(), "user cannot be nil")
 }
 
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_LogsEventFields() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","email":"test@example.com"}`), []string{"user.created"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("INFO", entry["level"])
+	suite.Equal("user.created", entry["event_type"])
+	suite.Equal("user.created", entry["routing_key"])
+	suite.Equal("test_exchange", entry["exchange"])
+	suite.NotEmpty(entry["event_id"])
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_LogsEventFieldsOnFailure() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","email":"test@example.com"}`), []string{"user.created"}, fmt.Errorf("publisher error"))
+
+	// Act
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("ERROR", entry["level"])
+	suite.Equal("user.created", entry["event_type"])
+	suite.Equal("user.created", entry["routing_key"])
+	suite.Equal("test_exchange", entry["exchange"])
+	suite.NotEmpty(entry["event_id"])
+	suite.Equal("message broker unavailable: publisher error", entry["error"])
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_LogsCallerContext() {
+	// Arrange
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	ctx := logging.WithRequestID(context.Background(), "req-9")
+	adapter := suite.outboxAdapter()
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	suite.mockPublisherPublish(createdBody, []string{EventUserCreated}, errors.New("connection refused")).Once()
+
+	// Act
+	err := adapter.PublishUserCreated(ctx, suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	failed := logs.FindMessage("Failed to publish event")
+	suite.Require().NotNil(failed)
+	suite.Equal("req-9", failed["request_id"])
+	buffered := logs.FindMessage("Event buffered in outbox")
+	suite.Require().NotNil(buffered)
+	suite.Equal("req-9", buffered["request_id"])
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_UpdatesBrokerConnected() {
+	// Arrange
+	body := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	suite.mockPublisher.On("PublishWithContext", mock.Anything, body, []string{"user.created"},
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	).Return(fmt.Errorf("publisher error")).Once()
+	suite.mockPublisher.On("PublishWithContext", mock.Anything, body, []string{"user.created"},
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+	).Return(nil).Once()
+
+	// Act & Assert
+	suite.Require().Error(suite.adapter.PublishUserCreated(context.Background(), suite.testUser))
+	suite.Equal(0.0, testutil.ToFloat64(metrics.BrokerConnected))
+
+	suite.Require().NoError(suite.adapter.PublishUserCreated(context.Background(), suite.testUser))
+	suite.Equal(1.0, testutil.ToFloat64(metrics.BrokerConnected))
+}
+
 // ===== PUBLISH USER DELETED TESTS =====
 
 func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_Success() {
@@ -149,7 +315,7 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_Success() {
 	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"user.deleted"}, nil)
 
 	// Act
-	err := suite.adapter.PublishUserDeleted(suite.testUser)
+	err := suite.adapter.PublishUserDeleted(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -165,7 +331,7 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_NilPublisher() {
 	}
 
 	// Act
-	err := adapter.PublishUserDeleted(suite.testUser)
+	err := adapter.PublishUserDeleted(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -178,7 +344,7 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_PublisherError() {
 	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"user.deleted"}, expectedError)
 
 	// Act
-	err := suite.adapter.PublishUserDeleted(suite.testUser)
+	err := suite.adapter.PublishUserDeleted(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -191,13 +357,296 @@ func (suite *RabbitMQAdapterTestSuite) TestPublishUserDeleted_NilUser() {
 	var user *models.User = nil
 
 	// Act
-	err := suite.adapter.PublishUserDeleted(user)
+	err := suite.adapter.PublishUserDeleted(context.Background(), user)
 
 	// Assert
 	suite.Require().Error(err)
 	suite.Contains(err.Error(), "user cannot be nil")
 }
 
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserCreated_LogsMaskedPayload() {
+	// Arrange
+	body := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	suite.mockPublisherPublish(body, []string{"user.created"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserCreated(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("Event published", entry["msg"])
+	suite.Equal(map[string]any{"user_id": suite.testUser.ID.String(), "email": "t***@example.com"}, entry["payload"])
+	suite.NotContains(suite.logs.String(), "test@example.com")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestLoggedPayload_UnknownEventType() {
+	suite.Nil(loggedPayload("user.renamed", []byte(`{"email":"test@example.com"}`)))
+	suite.Nil(loggedPayload(EventUserCreated, []byte("not json")))
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserSessionsRevoked_Success() {
+	// Arrange
+	revokedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.testUser.SessionsRevokedAt = &revokedAt
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","revoked_at":"2024-03-01T12:00:00Z"}`), []string{"user.sessions_revoked"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserSessionsRevoked(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockPublisher.AssertExpectations(suite.T())
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserSessionsRevoked_NotRevoked() {
+	// Act
+	err := suite.adapter.PublishUserSessionsRevoked(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "user with revoked sessions is required")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserSuspended_Success() {
+	// Arrange
+	suspendedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.testUser.SuspendedAt = &suspendedAt
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`","suspended_at":"2024-03-01T12:00:00Z"}`), []string{"user.suspended"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserSuspended(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockPublisher.AssertExpectations(suite.T())
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserSuspended_NotSuspended() {
+	// Act
+	err := suite.adapter.PublishUserSuspended(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "suspended user is required")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublishUserUnsuspended_Success() {
+	// Arrange
+	suite.mockPublisherPublish([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"user.unsuspended"}, nil)
+
+	// Act
+	err := suite.adapter.PublishUserUnsuspended(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockPublisher.AssertExpectations(suite.T())
+}
+
+// ===== DRAIN TESTS =====
+
+// confirmingAdapter returns an adapter in confirm mode with one published event waiting for a confirmation
+// that never arrives, the wait ends when the publish timeout elapses and the returned channel is closed
+func (suite *RabbitMQAdapterTestSuite) confirmingAdapter(publishTimeout time.Duration) (*RabbitMQAdapter, <-chan struct{}) {
+	adapter := &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config:    config.RabbitMQConfig{Exchange: "test_exchange", PublishTimeout: publishTimeout},
+		confirms:  true,
+	}
+	// A zero DeferredConfirmation is never settled, like a confirm lost when the channel reconnects
+	suite.mockPublisherConfirm([]byte(`{"user_id":"`+suite.testUser.ID.String()+`"}`), []string{"user.deleted"},
+		rabbitmq.PublisherConfirmation{&amqp.DeferredConfirmation{}})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		adapter.PublishUserDeleted(context.Background(), suite.testUser)
+	}()
+	suite.Require().Eventually(func() bool { return adapter.pendingConfirmations() == 1 }, time.Second, time.Millisecond)
+	return adapter, done
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestDrain_NothingPending() {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	err := suite.adapter.Drain(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestDrain_WaitsForSlowConfirm() {
+	// Arrange
+	confirmTimeout := 50 * time.Millisecond
+	adapter, done := suite.confirmingAdapter(confirmTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+
+	// Act
+	err := adapter.Drain(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Less(time.Since(start), time.Second)
+	suite.Equal(0, adapter.pendingConfirmations())
+	<-done
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestDrain_TimesOut() {
+	// Arrange
+	adapter, done := suite.confirmingAdapter(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := adapter.Drain(ctx)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Contains(err.Error(), "failed to drain 1 pending events")
+	<-done
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestDrain_LostConfirmationIsNotPending() {
+	// Arrange
+	adapter, done := suite.confirmingAdapter(20 * time.Millisecond)
+	<-done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := adapter.Drain(ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(0, adapter.pendingConfirmations())
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestHandleConfirmation_DoesNotSettlePending() {
+	// Arrange
+	adapter, done := suite.confirmingAdapter(50 * time.Millisecond)
+
+	// Act
+	adapter.handleConfirmation(rabbitmq.Confirmation{})
+
+	// Assert
+	suite.Equal(1, adapter.pendingConfirmations())
+	<-done
+}
+
+// ===== PUBLISH TIMEOUT TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_TimesOutOnSlowConfirm() {
+	// Arrange
+	timeout := 50 * time.Millisecond
+	adapter := &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config:    config.RabbitMQConfig{Exchange: "test_exchange", PublishTimeout: timeout},
+		confirms:  true,
+	}
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	// A zero DeferredConfirmation is never settled, like a confirm the broker is slow to send
+	suite.mockPublisherConfirm(deletedBody, []string{EventUserDeleted}, rabbitmq.PublisherConfirmation{&amqp.DeferredConfirmation{}}).Once()
+	suite.mockPublisherConfirm(deletedBody, []string{EventUserDeleted}, nil).Once()
+	start := time.Now()
+
+	// Act
+	err := adapter.PublishUserDeleted(context.Background(), suite.testUser)
+	elapsed := time.Since(start)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Contains(err.Error(), "publish timed out after 50ms")
+	suite.GreaterOrEqual(elapsed, timeout)
+	suite.Less(elapsed, time.Second)
+	suite.Require().NoError(adapter.PublishUserDeleted(context.Background(), suite.testUser))
+	suite.mockConn.AssertNotCalled(suite.T(), "Close")
+	suite.ErrorIs(err, ErrBrokerUnavailable)
+}
+
+// ===== PUBLISH ERROR TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_CallerCancelled() {
+	// Arrange
+	adapter := &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config:    config.RabbitMQConfig{Exchange: "test_exchange", PublishTimeout: time.Second},
+		confirms:  true,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.mockPublisherConfirm(deletedBody, []string{EventUserDeleted}, rabbitmq.PublisherConfirmation{&amqp.DeferredConfirmation{}}).
+		Run(func(mock.Arguments) { cancel() })
+
+	// Act
+	err := adapter.PublishUserDeleted(ctx, suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.ErrorIs(err, ErrPublishCancelled)
+	suite.ErrorIs(err, context.Canceled)
+	suite.NotErrorIs(err, ErrBrokerUnavailable)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_BrokerUnavailable() {
+	// Arrange
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.mockPublisherPublish(deletedBody, []string{EventUserDeleted}, errors.New("connection refused"))
+
+	// Act
+	err := suite.adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.ErrorIs(err, ErrBrokerUnavailable)
+	suite.NotErrorIs(err, ErrPublishCancelled)
+	suite.Equal(0.0, testutil.ToFloat64(metrics.BrokerConnected))
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_RejectsOversizedPayload() {
+	// Arrange
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	adapter := &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config:    config.RabbitMQConfig{Exchange: "test_exchange", MaxPayloadBytes: len(deletedBody)},
+	}
+	suite.mockPublisherPublish(deletedBody, []string{EventUserDeleted}, nil)
+
+	// Act
+	createdErr := adapter.PublishUserCreated(context.Background(), suite.testUser)
+	deletedErr := adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(createdErr)
+	suite.ErrorIs(createdErr, ErrPayloadTooLarge)
+	suite.Contains(createdErr.Error(), fmt.Sprintf("%d bytes, limit is %d", len(createdBody), len(deletedBody)))
+	suite.Require().NoError(deletedErr)
+	suite.mockPublisher.AssertNumberOfCalls(suite.T(), "PublishWithContext", 1)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_OversizedPayloadIsNotBuffered() {
+	// Arrange
+	adapter := suite.outboxAdapter()
+	adapter.config.MaxPayloadBytes = 8
+
+	// Act
+	err := adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().ErrorIs(err, ErrPayloadTooLarge)
+	suite.Equal(0, adapter.outbox.Len())
+}
+
 // ===== CLOSE TESTS =====
 
 func (suite *RabbitMQAdapterTestSuite) TestClose_Success() {
@@ -212,6 +661,167 @@ func (suite *RabbitMQAdapterTestSuite) TestClose_Success() {
 	suite.mockConn.AssertExpectations(suite.T())
 }
 
+// ===== ROUTING KEY TESTS =====
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_UsesConfiguredRoutingKeys() {
+	// Arrange
+	adapter := &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config: config.RabbitMQConfig{
+			Exchange: "test_exchange",
+			RoutingKeys: map[string]string{
+				EventUserCreated: "auth.user.created",
+				EventUserDeleted: "auth.user.deleted",
+			},
+		},
+	}
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.mockPublisherPublish(createdBody, []string{"auth.user.created"}, nil)
+	suite.mockPublisherPublish(deletedBody, []string{"auth.user.deleted"}, nil)
+
+	// Act
+	createdErr := adapter.PublishUserCreated(context.Background(), suite.testUser)
+	deletedErr := adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(createdErr)
+	suite.Require().NoError(deletedErr)
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestPublish_DefaultRoutingKeysCoverEventTypes() {
+	for _, eventType := range []string{EventUserCreated, EventUserDeleted, EventUserSessionsRevoked, EventUserSuspended, EventUserUnsuspended} {
+		suite.Equal(eventType, config.DefaultRoutingKeys[eventType], eventType)
+	}
+	suite.Len(config.DefaultRoutingKeys, 5)
+}
+
+// ===== OUTBOX TESTS =====
+
+// outboxAdapter returns an adapter with an empty outbox in a temporary directory
+func (suite *RabbitMQAdapterTestSuite) outboxAdapter() *RabbitMQAdapter {
+	outbox, err := NewOutbox(filepath.Join(suite.T().TempDir(), "outbox.jsonl"))
+	suite.Require().NoError(err)
+	return &RabbitMQAdapter{
+		publisher: suite.mockPublisher,
+		conn:      suite.mockConn,
+		config:    suite.config,
+		outbox:    outbox,
+	}
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestOutbox_BuffersWhileDisconnected() {
+	// Arrange
+	adapter := suite.outboxAdapter()
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	suite.mockPublisherPublish(createdBody, []string{EventUserCreated}, errors.New("connection refused")).Once()
+
+	// Act
+	createdErr := adapter.PublishUserCreated(context.Background(), suite.testUser)
+	deletedErr := adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(createdErr)
+	suite.Require().NoError(deletedErr)
+	suite.Equal(2, adapter.outbox.Len())
+	suite.Equal(EventUserCreated, adapter.outbox.entries[0].EventType)
+	suite.Equal(EventUserDeleted, adapter.outbox.entries[1].EventType)
+	suite.mockPublisher.AssertNumberOfCalls(suite.T(), "PublishWithContext", 1)
+	suite.Equal("Event buffered in outbox", suite.lastLogEntry()["msg"])
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestOutbox_ReplaysOnReconnect() {
+	// Arrange
+	adapter := suite.outboxAdapter()
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.mockPublisherPublish(createdBody, []string{EventUserCreated}, errors.New("connection refused")).Once()
+	suite.Require().NoError(adapter.PublishUserCreated(context.Background(), suite.testUser))
+	suite.Require().NoError(adapter.PublishUserDeleted(context.Background(), suite.testUser))
+
+	var replayed []string
+	suite.mockPublisher.On("PublishWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			replayed = append(replayed, args.Get(2).([]string)[0])
+		}).Return(nil)
+	suite.mockClose(nil)
+
+	// Act
+	adapter.startOutboxReplay(10 * time.Millisecond)
+	suite.Eventually(func() bool { return adapter.outbox.Len() == 0 }, time.Second, 10*time.Millisecond)
+	adapter.Close()
+
+	// Assert
+	suite.Equal([]string{EventUserCreated, EventUserDeleted}, replayed)
+	suite.Equal(createdBody, suite.mockPublisher.Calls[1].Arguments.Get(1))
+	suite.Equal(deletedBody, suite.mockPublisher.Calls[2].Arguments.Get(1))
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestOutbox_StartsDisconnectedAndReplaysOnConnect() {
+	// Arrange
+	outbox, err := NewOutbox(filepath.Join(suite.T().TempDir(), "outbox.jsonl"))
+	suite.Require().NoError(err)
+	dials := 0
+	adapter := &RabbitMQAdapter{
+		config:   suite.config,
+		confirms: true,
+		outbox:   outbox,
+		connect: func() (IRabbitMQConn, IRabbitMQPublisher, error) {
+			dials++
+			if dials == 1 {
+				return nil, nil, errors.New("connection refused")
+			}
+			return suite.mockConn, suite.mockPublisher, nil
+		},
+	}
+	createdBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `","email":"test@example.com"}`)
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.Require().NoError(adapter.PublishUserCreated(context.Background(), suite.testUser))
+	suite.Require().NoError(adapter.PublishUserDeleted(context.Background(), suite.testUser))
+	suite.Require().Equal(2, adapter.outbox.Len())
+	suite.mockPublisher.AssertNotCalled(suite.T(), "PublishWithDeferredConfirmWithContext")
+
+	suite.mockPublisher.On("NotifyPublish", mock.Anything).Return().Once()
+	suite.mockPublisherConfirm(createdBody, []string{EventUserCreated}, nil).Once()
+	suite.mockPublisherConfirm(deletedBody, []string{EventUserDeleted}, nil).Once()
+	suite.mockClose(nil)
+
+	// Act
+	adapter.startOutboxReplay(10 * time.Millisecond)
+	suite.Eventually(func() bool { return adapter.outbox.Len() == 0 }, time.Second, 10*time.Millisecond)
+	adapter.Close()
+
+	// Assert
+	suite.Equal(2, dials)
+	suite.Equal(createdBody, suite.mockPublisher.Calls[1].Arguments.Get(1))
+	suite.Equal(deletedBody, suite.mockPublisher.Calls[2].Arguments.Get(1))
+	suite.Equal(1.0, testutil.ToFloat64(metrics.BrokerConnected))
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestNewBufferingRabbitMQAdapter_RequiresOutbox() {
+	// Act
+	adapter, err := NewBufferingRabbitMQAdapter(config.RabbitMQConfig{URL: "amqp://localhost:1", Exchange: "test_exchange"})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(adapter)
+	suite.Contains(err.Error(), "outbox is required")
+}
+
+func (suite *RabbitMQAdapterTestSuite) TestOutbox_DisabledReturnsError() {
+	// Arrange
+	deletedBody := []byte(`{"user_id":"` + suite.testUser.ID.String() + `"}`)
+	suite.mockPublisherPublish(deletedBody, []string{EventUserDeleted}, errors.New("connection refused"))
+
+	// Act
+	err := suite.adapter.PublishUserDeleted(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "connection refused")
+}
+
 func TestRabbitMQAdapterTestSuite(t *testing.T) {
 	suite.Run(t, new(RabbitMQAdapterTestSuite))
 }
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auth_service"
+
+// Registry holds all auth service collectors exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// DBQueryDuration observes the duration of every database query
+	DBQueryDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of database queries in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// DBSlowQueries counts queries exceeding the configured slow-query threshold
+	DBSlowQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "slow_queries_total",
+		Help:      "Number of database queries exceeding the slow-query threshold.",
+	})
+
+	// BrokerConnected reports whether the message broker was reachable on the last attempt
+	BrokerConnected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "broker",
+		Name:      "connected",
+		Help:      "Whether the message broker is connected (1) or unavailable (0).",
+	})
+
+	// TokensIssued counts signed tokens by token type
+	TokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "tokens_issued_total",
+		Help:      "Number of tokens issued, by token type.",
+	}, []string{"type"})
+
+	// MalformedEmails reports how many values utils.MaskEmail could not parse as an email address
+	MalformedEmails = prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logging",
+		Name:      "malformed_emails_total",
+		Help:      "Number of malformed email addresses masked for logging.",
+	}, func() float64 {
+		return float64(utils.MalformedEmailCount())
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		DBQueryDuration,
+		DBSlowQueries,
+		BrokerConnected,
+		TokensIssued,
+		MalformedEmails,
+	)
+}
+
+// Handler returns the HTTP handler serving metrics from Registry
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// DBPoolCollector samples connection pool stats on every scrape
+type DBPoolCollector struct {
+	stats func() sql.DBStats
+	open  *prometheus.Desc
+	inUse *prometheus.Desc
+	idle  *prometheus.Desc
+}
+
+// NewDBPoolCollector creates a collector for the pool behind stats, labeled with the pool role
+func NewDBPoolCollector(role string, stats func() sql.DBStats) *DBPoolCollector {
+	labels := prometheus.Labels{"role": role}
+	return &DBPoolCollector{
+		stats: stats,
+		open: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_open"),
+			"Number of established database connections.", nil, labels),
+		inUse: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_in_use"),
+			"Number of database connections currently in use.", nil, labels),
+		idle: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_idle"),
+			"Number of idle database connections.", nil, labels),
+	}
+}
+
+func (c *DBPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+}
+
+func (c *DBPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+}
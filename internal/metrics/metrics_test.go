@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// gatherGauges collects gauge values from a registry keyed by metric name
+func gatherGauges(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func TestDBPoolCollector_LiveAdapter(t *testing.T) {
+	// Arrange
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	adapter := repositories.NewGormAdapterFromDB(db)
+	defer adapter.Close()
+	require.NoError(t, db.Exec("SELECT 1").Error)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewDBPoolCollector("primary", adapter.Stats))
+
+	// Act
+	gauges := gatherGauges(t, registry)
+
+	// Assert
+	require.Contains(t, gauges, "auth_service_db_connections_open")
+	require.Contains(t, gauges, "auth_service_db_connections_in_use")
+	require.Contains(t, gauges, "auth_service_db_connections_idle")
+	stats := adapter.Stats()
+	assert.GreaterOrEqual(t, gauges["auth_service_db_connections_open"], 1.0)
+	assert.Equal(t, float64(stats.OpenConnections), gauges["auth_service_db_connections_open"])
+	assert.Equal(t, float64(stats.InUse), gauges["auth_service_db_connections_in_use"])
+	assert.Equal(t, float64(stats.Idle), gauges["auth_service_db_connections_idle"])
+}
+
+func TestDBPoolCollector_RoleLabel(t *testing.T) {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewDBPoolCollector("replica", adapter.Stats))
+
+	// Act
+	families, err := registry.Gather()
+
+	// Assert
+	require.NoError(t, err)
+	require.NotEmpty(t, families)
+	for _, family := range families {
+		labels := family.GetMetric()[0].GetLabel()
+		require.Len(t, labels, 1)
+		assert.Equal(t, "role", labels[0].GetName())
+		assert.Equal(t, "replica", labels[0].GetValue())
+	}
+}
+
+func TestMalformedEmails_CountsMaskEmailFailures(t *testing.T) {
+	// Arrange
+	before := testutil.ToFloat64(metrics.MalformedEmails)
+
+	// Act
+	utils.MaskEmail("john.doe@example.com")
+	afterValid := testutil.ToFloat64(metrics.MalformedEmails)
+	utils.MaskEmail("not-an-email")
+	afterInvalid := testutil.ToFloat64(metrics.MalformedEmails)
+
+	// Assert
+	assert.Equal(t, before, afterValid)
+	assert.Equal(t, before+1, afterInvalid)
+}
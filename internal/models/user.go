@@ -7,11 +7,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID        uuid.UUID      `json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty"`
-	Email     string         `json:"email" validate:"required,email"`
-	Password  string         `json:"password" validate:"required,password"`
+	ID          uuid.UUID      `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty"`
+	Email       string         `json:"email" validate:"required,email" mask:"email"`
+	Password    string         `json:"password" validate:"required,password" mask:"redact"`
+	Role        string         `json:"role" gorm:"default:user"`
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+	Profile     Profile        `json:"profile" gorm:"embedded"`
+
+	// SessionsRevokedAt invalidates every token issued in an earlier second, nil when never revoked
+	SessionsRevokedAt *time.Time `json:"sessions_revoked_at,omitempty"`
+	// FailedAttempts counts consecutive failed logins, see IUserRepository.IncrementFailedAttempts
+	FailedAttempts int `json:"failed_attempts,omitempty" gorm:"not null;default:0"`
+	// SuspendedAt is when the account was suspended, nil when it is active
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+}
+
+// Profile holds optional user profile fields, empty values mean unset
+type Profile struct {
+	DisplayName string `json:"display_name,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
 }
@@ -1,12 +1,15 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormAdapter adapter for GORM DB
@@ -19,7 +22,9 @@ func NewGormAdapter(dbConfig *config.DBConfig) (IDatabase, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.DBName, dbConfig.SSLMode)
 
-	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{
+		Logger: NewGormLogger(dbConfig.SlowQueryThreshold),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -27,6 +32,18 @@ func NewGormAdapter(dbConfig *config.DBConfig) (IDatabase, error) {
 	return &GormAdapter{db: db}, nil
 }
 
+// NewGormReadReplicaAdapter creates an adapter connected to the configured read replica
+// The replica shares credentials and database name with the primary
+func NewGormReadReplicaAdapter(dbConfig *config.DBConfig) (IDatabase, error) {
+	if dbConfig.ReadReplicaHost == "" {
+		return nil, errors.New("read replica host is not configured")
+	}
+
+	replicaConfig := *dbConfig
+	replicaConfig.Host = dbConfig.ReadReplicaHost
+	return NewGormAdapter(&replicaConfig)
+}
+
 // NewGormAdapterFromDB creates a new adapter from existing GORM DB (for testing)
 func NewGormAdapterFromDB(db *gorm.DB) IDatabase {
 	return &GormAdapter{db: db}
@@ -67,9 +84,199 @@ func (g *GormAdapter) Count(value *int64) IDatabase {
 	return &GormAdapter{db: g.db.Count(value)}
 }
 
+func (g *GormAdapter) Update(column string, value interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Update(column, value)}
+}
+
+// UpdateColumn updates a single column without hooks or touching updated_at
+func (g *GormAdapter) UpdateColumn(column string, value interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.UpdateColumn(column, value)}
+}
+
+// Increment adds delta to column in a single UPDATE, without hooks or touching updated_at
+func (g *GormAdapter) Increment(column string, delta int) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.UpdateColumn(column, gorm.Expr(g.db.Statement.Quote(column)+" + ?", delta))}
+}
+
+// Returning makes the next update or insert read the given columns back into its model
+func (g *GormAdapter) Returning(columns ...string) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	returning := clause.Returning{Columns: make([]clause.Column, len(columns))}
+	for i, column := range columns {
+		returning.Columns[i] = clause.Column{Name: column}
+	}
+	return &GormAdapter{db: g.db.Clauses(returning)}
+}
+
+func (g *GormAdapter) Find(dest interface{}, conds ...interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Find(dest, conds...)}
+}
+
+func (g *GormAdapter) Order(value interface{}) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Order(value)}
+}
+
+func (g *GormAdapter) Limit(limit int) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Limit(limit)}
+}
+
+func (g *GormAdapter) Offset(offset int) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Offset(offset)}
+}
+
+// WithContext returns an adapter whose queries are bound to ctx
+func (g *GormAdapter) WithContext(ctx context.Context) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.WithContext(ctx)}
+}
+
+// WithSchema returns an adapter whose next query targets its model's table in schema
+// The table is qualified when the query runs, once the model is known
+func (g *GormAdapter) WithSchema(schema string) IDatabase {
+	if g.db == nil {
+		return &GormAdapter{db: nil}
+	}
+	return &GormAdapter{db: g.db.Scopes(schemaScope(schema))}
+}
+
+// schemaScope qualifies the statement's table with schema unless a table was set explicitly
+func schemaScope(schema string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		model := tx.Statement.Model
+		if model == nil {
+			model = tx.Statement.Dest
+		}
+		if tx.Statement.Table != "" || model == nil {
+			return tx
+		}
+		if err := tx.Statement.Parse(model); err != nil {
+			_ = tx.AddError(err)
+			return tx
+		}
+		// Table is qualified along with TableExpr because some dialects build INSERT from Table alone
+		qualified := schema + "." + tx.Statement.Table
+		tx.Statement.TableExpr = &clause.Expr{SQL: tx.Statement.Quote(qualified)}
+		tx.Statement.Table = qualified
+		return tx
+	}
+}
+
 func (g *GormAdapter) GetError() error {
 	if g.db == nil {
 		return errors.New("database is nil")
 	}
 	return g.db.Error
 }
+
+// RowsAffected returns the number of rows the last statement affected
+func (g *GormAdapter) RowsAffected() int64 {
+	if g.db == nil {
+		return 0
+	}
+	return g.db.RowsAffected
+}
+
+// Stats returns connection pool statistics, or zero stats when there is no pool
+func (g *GormAdapter) Stats() sql.DBStats {
+	if g.db == nil {
+		return sql.DBStats{}
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
+
+// Ping checks that the database is reachable, honoring ctx cancellation
+func (g *GormAdapter) Ping(ctx context.Context) error {
+	if g.db == nil {
+		return errors.New("database is nil")
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// defaultMaxIdleConns is the idle connection limit database/sql applies when none is configured
+const defaultMaxIdleConns = 2
+
+// Warmup opens and pings n pool connections up front so the first requests after startup
+// do not pay for connecting. The idle limit is raised to n so the warmed connections stay open,
+// and n is capped at the pool's open connection limit when one is set
+func (g *GormAdapter) Warmup(ctx context.Context, n int) error {
+	if g.db == nil {
+		return errors.New("database is nil")
+	}
+	if n <= 0 {
+		return nil
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if maxOpen := sqlDB.Stats().MaxOpenConnections; maxOpen > 0 && n > maxOpen {
+		n = maxOpen
+	}
+	if n > defaultMaxIdleConns {
+		sqlDB.SetMaxIdleConns(n)
+	}
+
+	// All connections are held at once, otherwise the pool would hand out the same one again
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open connection %d of %d: %w", i+1, n, err)
+		}
+		conns = append(conns, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to ping connection %d of %d: %w", i+1, n, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying connection pool; closing an already closed pool is a no-op
+func (g *GormAdapter) Close() error {
+	if g.db == nil {
+		return nil
+	}
+	sqlDB, err := g.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.Close()
+}
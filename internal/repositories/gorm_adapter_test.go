@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
@@ -76,6 +77,149 @@ func (suite *GormAdapterTestSuite) TestNewGormAdapterWithConfig_Success() {
 	suite.Require().Nil(adapter)
 }
 
+func (suite *GormAdapterTestSuite) TestNewGormReadReplicaAdapter_NotConfigured() {
+	// Arrange
+	dbConfig := config.DBConfig{Host: "localhost"}
+
+	// Act
+	adapter, err := repositories.NewGormReadReplicaAdapter(&dbConfig)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Require().Nil(adapter)
+	suite.Contains(err.Error(), "read replica host is not configured")
+}
+
+// ===== CLOSE TESTS =====
+
+func (suite *GormAdapterTestSuite) TestClose_Success() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+
+	// Act
+	err := adapter.Close()
+
+	// Assert
+	suite.Require().NoError(err)
+	var user TestUser
+	suite.Require().Error(adapter.First(&user).GetError())
+}
+
+func (suite *GormAdapterTestSuite) TestClose_Twice() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	suite.Require().NoError(adapter.Close())
+
+	// Act
+	err := adapter.Close()
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *GormAdapterTestSuite) TestClose_NilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	err := adapter.Close()
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+// ===== PING TESTS =====
+
+func (suite *GormAdapterTestSuite) TestPing_Success() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+
+	// Act
+	err := adapter.Ping(context.Background())
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *GormAdapterTestSuite) TestPing_NilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	err := adapter.Ping(context.Background())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database is nil")
+}
+
+func (suite *GormAdapterTestSuite) TestPing_Closed() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	suite.Require().NoError(adapter.Close())
+
+	// Act
+	err := adapter.Ping(context.Background())
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+// ===== WARMUP TESTS =====
+
+func (suite *GormAdapterTestSuite) TestWarmup_OpensRequestedConnections() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+
+	// Act
+	err := adapter.Warmup(context.Background(), 5)
+
+	// Assert
+	suite.Require().NoError(err)
+	stats := adapter.Stats()
+	suite.Equal(5, stats.OpenConnections)
+	suite.Equal(5, stats.Idle)
+}
+
+func (suite *GormAdapterTestSuite) TestWarmup_CappedAtMaxOpenConnections() {
+	// Arrange
+	db, adapter := suite.setupTestDB()
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(3)
+
+	// Act
+	err = adapter.Warmup(context.Background(), 10)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(3, adapter.Stats().OpenConnections)
+}
+
+func (suite *GormAdapterTestSuite) TestWarmup_NilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	err := adapter.Warmup(context.Background(), 2)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database is nil")
+}
+
+func (suite *GormAdapterTestSuite) TestWarmup_Closed() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	suite.Require().NoError(adapter.Close())
+
+	// Act
+	err := adapter.Warmup(context.Background(), 2)
+
+	// Assert
+	suite.Require().Error(err)
+}
+
 // ===== METHOD TESTS =====
 
 func (suite *GormAdapterTestSuite) TestCreateWithRealDB() {
@@ -267,6 +411,108 @@ func (suite *GormAdapterTestSuite) TestModelWithRealDB() {
 	suite.Require().NoError(result.GetError())
 }
 
+func (suite *GormAdapterTestSuite) TestUpdateWithNilDB() {
+	// Arrange
+	adapter := repositories.NewGormAdapterFromDB(nil)
+
+	// Act
+	result := adapter.Update("email", "new@example.com")
+
+	// Assert
+	suite.Require().NotNil(result)
+	suite.Require().Error(result.GetError())
+	suite.Contains(result.GetError().Error(), "database is nil")
+}
+
+func (suite *GormAdapterTestSuite) TestUpdateWithRealDB() {
+	// Arrange
+	db, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "old@example.com"})
+
+	// Act
+	result := adapter.Model(&TestUser{}).Where("email = ?", "old@example.com").Update("email", "new@example.com")
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	var count int64
+	db.Model(&TestUser{}).Where("email = ?", "new@example.com").Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *GormAdapterTestSuite) TestUpdateColumnWithRealDB() {
+	// Arrange
+	db, adapter := suite.setupTestDB()
+	adapter.Create(&TestUser{Email: "old@example.com"})
+
+	// Act
+	result := adapter.Model(&TestUser{}).Where("email = ?", "old@example.com").UpdateColumn("email", "new@example.com")
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	var count int64
+	db.Model(&TestUser{}).Where("email = ?", "new@example.com").Count(&count)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *GormAdapterTestSuite) TestQueryMethodsWithNilDB() {
+	adapter := repositories.NewGormAdapterFromDB(nil)
+	var users []TestUser
+
+	results := map[string]repositories.IDatabase{
+		"Find":         adapter.Find(&users),
+		"Order":        adapter.Order("email"),
+		"Limit":        adapter.Limit(10),
+		"Offset":       adapter.Offset(10),
+		"UpdateColumn": adapter.UpdateColumn("email", "new@example.com"),
+		"Increment":    adapter.Increment("logins", 1),
+		"Returning":    adapter.Returning("logins"),
+		"WithContext":  adapter.WithContext(context.Background()),
+	}
+
+	for name, result := range results {
+		suite.Run(name, func() {
+			suite.Require().Error(result.GetError())
+			suite.Contains(result.GetError().Error(), "database is nil")
+		})
+	}
+}
+
+func (suite *GormAdapterTestSuite) TestOffsetWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	for _, email := range []string{"c@test.com", "a@test.com", "b@test.com"} {
+		adapter.Create(&TestUser{Email: email})
+	}
+
+	// Act
+	var users []TestUser
+	result := adapter.Order("email").Limit(2).Offset(1).Find(&users)
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Require().Len(users, 2)
+	suite.Equal("b@test.com", users[0].Email)
+	suite.Equal("c@test.com", users[1].Email)
+}
+
+func (suite *GormAdapterTestSuite) TestFindOrderLimitWithRealDB() {
+	// Arrange
+	_, adapter := suite.setupTestDB()
+	for _, email := range []string{"c@test.com", "a@test.com", "b@test.com"} {
+		adapter.Create(&TestUser{Email: email})
+	}
+
+	// Act
+	var users []TestUser
+	result := adapter.WithContext(context.Background()).Order("email").Limit(2).Find(&users)
+
+	// Assert
+	suite.Require().NoError(result.GetError())
+	suite.Require().Len(users, 2)
+	suite.Equal("a@test.com", users[0].Email)
+	suite.Equal("b@test.com", users[1].Email)
+}
+
 // Run tests
 func TestGormAdapterTestSuite(t *testing.T) {
 	suite.Run(t, new(GormAdapterTestSuite))
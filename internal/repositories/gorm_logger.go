@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultSlowQueryThreshold is used when no slow-query threshold is configured
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+var (
+	bcryptHashPattern = regexp.MustCompile(`^\$2[abxy]\$\d{2}\$`)
+	jwtPattern        = regexp.MustCompile(`^eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+)
+
+// GormLogger bridges GORM logging to the default slog logger
+// Queries are logged at DEBUG, slow queries at WARN and failed queries at ERROR
+// Every query duration is also recorded in the database metrics
+type GormLogger struct {
+	SlowThreshold time.Duration
+	level         logger.LogLevel
+}
+
+// NewGormLogger creates a GORM logger with the given slow-query threshold
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowQueryThreshold
+	}
+	return &GormLogger{
+		SlowThreshold: slowThreshold,
+		level:         logger.Info,
+	}
+}
+
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		slog.Default().InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.Default().WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		slog.Default().ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace records metrics and logs a finished query with its duration and affected rows
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	metrics.DBQueryDuration.Observe(elapsed.Seconds())
+	if elapsed > l.SlowThreshold {
+		metrics.DBSlowQueries.Inc()
+	}
+
+	if l.level <= logger.Silent {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		slog.Default().ErrorContext(ctx, "database query failed", append(attrs, slog.String("error", err.Error()))...)
+	case elapsed > l.SlowThreshold && l.level >= logger.Warn:
+		slog.Default().WarnContext(ctx, "slow database query", append(attrs, slog.Duration("threshold", l.SlowThreshold))...)
+	case l.level >= logger.Info:
+		slog.Default().DebugContext(ctx, "database query", attrs...)
+	}
+}
+
+// ParamsFilter replaces bound parameters that look like secrets with utils.RedactionPlaceholder before the SQL is rendered
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	filtered := make([]interface{}, len(params))
+	for i, param := range params {
+		if s, ok := param.(string); ok && looksLikeSecret(s) {
+			filtered[i] = utils.RedactionPlaceholder()
+			continue
+		}
+		filtered[i] = param
+	}
+	return sql, filtered
+}
+
+// looksLikeSecret reports whether a value resembles a password hash or a token
+func looksLikeSecret(value string) bool {
+	return bcryptHashPattern.MatchString(value) || jwtPattern.MatchString(value)
+}
@@ -0,0 +1,210 @@
+package repositories_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
+	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type operationKey struct{}
+
+// operationHandler copies the operation stored in the context onto each record
+type operationHandler struct {
+	slog.Handler
+}
+
+func (h operationHandler) Handle(ctx context.Context, r slog.Record) error {
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		r.AddAttrs(slog.String("operation", op))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+type GormLoggerTestSuite struct {
+	suite.Suite
+	buf           *bytes.Buffer
+	defaultLogger *slog.Logger
+}
+
+func (suite *GormLoggerTestSuite) SetupTest() {
+	suite.buf = &bytes.Buffer{}
+	suite.defaultLogger = slog.Default()
+	handler := slog.NewJSONHandler(suite.buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	slog.SetDefault(slog.New(operationHandler{Handler: handler}))
+}
+
+func (suite *GormLoggerTestSuite) TearDownTest() {
+	slog.SetDefault(suite.defaultLogger)
+}
+
+// setupTestDB creates in-memory SQLite database using the given GORM logger
+func (suite *GormLoggerTestSuite) setupTestDB(gormLogger *repositories.GormLogger) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormLogger})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&TestUser{}))
+	suite.buf.Reset()
+	return db
+}
+
+// entries parses captured JSON log lines
+func (suite *GormLoggerTestSuite) entries() []map[string]any {
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(suite.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		suite.Require().NoError(json.Unmarshal([]byte(line), &entry))
+		result = append(result, entry)
+	}
+	return result
+}
+
+// histogram reads the current state of a histogram metric
+func (suite *GormLoggerTestSuite) histogram(h prometheus.Histogram) *dto.Histogram {
+	var m dto.Metric
+	suite.Require().NoError(h.Write(&m))
+	return m.GetHistogram()
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_QueryLoggedAtDebugWithContext() {
+	// Arrange
+	db := suite.setupTestDB(repositories.NewGormLogger(time.Minute))
+	ctx := context.WithValue(context.Background(), operationKey{}, "create_user")
+
+	// Act
+	err := db.WithContext(ctx).Create(&TestUser{Email: "test@example.com"}).Error
+
+	// Assert
+	suite.Require().NoError(err)
+	entries := suite.entries()
+	suite.Require().Len(entries, 1)
+	suite.Equal("DEBUG", entries[0]["level"])
+	suite.Equal("database query", entries[0]["msg"])
+	suite.Equal("create_user", entries[0]["operation"])
+	suite.Contains(entries[0]["sql"], "INSERT INTO")
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_SlowQueryLoggedAtWarn() {
+	// Arrange
+	gormLogger := repositories.NewGormLogger(time.Nanosecond)
+
+	// Act
+	gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+
+	// Assert
+	entries := suite.entries()
+	suite.Require().Len(entries, 1)
+	suite.Equal("WARN", entries[0]["level"])
+	suite.Equal("slow database query", entries[0]["msg"])
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_SlowQueryRecordsMetrics() {
+	// Arrange
+	gormLogger := repositories.NewGormLogger(100 * time.Millisecond)
+	slowBefore := testutil.ToFloat64(metrics.DBSlowQueries)
+	histBefore := suite.histogram(metrics.DBQueryDuration)
+
+	// Act
+	gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT pg_sleep(1)", 1
+	}, nil)
+
+	// Assert
+	histAfter := suite.histogram(metrics.DBQueryDuration)
+	suite.Equal(slowBefore+1, testutil.ToFloat64(metrics.DBSlowQueries))
+	suite.Equal(histBefore.GetSampleCount()+1, histAfter.GetSampleCount())
+	suite.GreaterOrEqual(histAfter.GetSampleSum()-histBefore.GetSampleSum(), 1.0)
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_FastQueryIsNotCountedAsSlow() {
+	// Arrange
+	gormLogger := repositories.NewGormLogger(time.Minute)
+	slowBefore := testutil.ToFloat64(metrics.DBSlowQueries)
+	histBefore := suite.histogram(metrics.DBQueryDuration)
+
+	// Act
+	gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT 1", 1
+	}, nil)
+
+	// Assert
+	suite.Equal(slowBefore, testutil.ToFloat64(metrics.DBSlowQueries))
+	suite.Equal(histBefore.GetSampleCount()+1, suite.histogram(metrics.DBQueryDuration).GetSampleCount())
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_ErrorLoggedAtError() {
+	// Arrange
+	db := suite.setupTestDB(repositories.NewGormLogger(time.Minute))
+
+	// Act
+	err := db.Exec("SELECT * FROM missing_table").Error
+
+	// Assert
+	suite.Require().Error(err)
+	entries := suite.entries()
+	suite.Require().Len(entries, 1)
+	suite.Equal("ERROR", entries[0]["level"])
+	suite.Contains(entries[0]["error"], "missing_table")
+}
+
+func (suite *GormLoggerTestSuite) TestTrace_RecordNotFoundIsNotAnError() {
+	// Arrange
+	db := suite.setupTestDB(repositories.NewGormLogger(time.Minute))
+
+	// Act
+	var user TestUser
+	err := db.Where("email = ?", "missing@example.com").First(&user).Error
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	entries := suite.entries()
+	suite.Require().Len(entries, 1)
+	suite.Equal("DEBUG", entries[0]["level"])
+}
+
+func (suite *GormLoggerTestSuite) TestParamsFilter_RedactsSecrets() {
+	// Arrange
+	db := suite.setupTestDB(repositories.NewGormLogger(time.Minute))
+	hash := "$2a$10$abcdefghijklmnopqrstuuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ01"
+
+	// Act
+	err := db.Where("email = ?", hash).Find(&[]TestUser{}).Error
+
+	// Assert
+	suite.Require().NoError(err)
+	entries := suite.entries()
+	suite.Require().Len(entries, 1)
+	suite.NotContains(entries[0]["sql"], hash)
+	suite.Contains(entries[0]["sql"], "[REDACTED]")
+}
+
+func (suite *GormLoggerTestSuite) TestParamsFilter_KeepsRegularValues() {
+	// Arrange
+	gormLogger := repositories.NewGormLogger(time.Minute)
+
+	// Act
+	_, params := gormLogger.ParamsFilter(context.Background(), "email = ?", "test@example.com", 42)
+
+	// Assert
+	suite.Equal([]interface{}{"test@example.com", 42}, params)
+}
+
+func TestGormLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(GormLoggerTestSuite))
+}
@@ -1,12 +1,35 @@
 package repositories
 
-import "github.com/Koshsky/subs-service/auth-service/internal/models"
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
 
 //go:generate mockery --name=IUserRepository --output=./mocks --outpkg=mocks --filename=IUserRepository.go
 type IUserRepository interface {
-	CreateUser(user *models.User) error
-	GetUserByEmail(email string) (*models.User, error)
-	UserExists(email string) (bool, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	TouchLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	RevokeSessions(ctx context.Context, id uuid.UUID, at time.Time) error
+	SessionsRevokedAt(ctx context.Context, id uuid.UUID) (*time.Time, error)
+	IncrementFailedAttempts(ctx context.Context, id uuid.UUID) (int, error)
+	ResetFailedAttempts(ctx context.Context, id uuid.UUID) error
+	SuspendUser(ctx context.Context, id uuid.UUID, at time.Time) error
+	UnsuspendUser(ctx context.Context, id uuid.UUID) error
+	UserExists(ctx context.Context, email string) (bool, error)
+	HasUsers(ctx context.Context) (bool, error)
+	SearchByEmailPrefix(ctx context.Context, prefix string, limit int) ([]models.User, error)
+	ListUsersByRole(ctx context.Context, role string, limit, offset int) ([]models.User, error)
+	ListUsersAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]models.User, uuid.UUID, error)
 }
 
 //go:generate mockery --name=IDatabase --output=./mocks --outpkg=mocks --filename=IDatabase.go
@@ -16,9 +39,26 @@ type IDatabase interface {
 	First(dest interface{}, conds ...interface{}) IDatabase
 	Model(value interface{}) IDatabase
 	Count(value *int64) IDatabase
+	Update(column string, value interface{}) IDatabase
+	UpdateColumn(column string, value interface{}) IDatabase
+	Increment(column string, delta int) IDatabase
+	Returning(columns ...string) IDatabase
+	Find(dest interface{}, conds ...interface{}) IDatabase
+	Order(value interface{}) IDatabase
+	Limit(limit int) IDatabase
+	Offset(offset int) IDatabase
+	WithContext(ctx context.Context) IDatabase
+	WithSchema(schema string) IDatabase
 	GetError() error
+	RowsAffected() int64
+	Stats() sql.DBStats
+	Ping(ctx context.Context) error
+	Warmup(ctx context.Context, n int) error
+	Close() error
 }
 
 // Interface compliance checks - will fail at compile time if interfaces are not implemented
 var _ IUserRepository = (*UserRepository)(nil)
 var _ IDatabase = (*GormAdapter)(nil)
+var _ logger.Interface = (*GormLogger)(nil)
+var _ gorm.ParamsFilter = (*GormLogger)(nil)
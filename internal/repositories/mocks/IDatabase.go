@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	context "context"
+
 	repositories "github.com/Koshsky/subs-service/auth-service/internal/repositories"
 	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
 )
 
 // IDatabase is an autogenerated mock type for the IDatabase type
@@ -12,6 +16,24 @@ type IDatabase struct {
 	mock.Mock
 }
 
+// Close provides a mock function with no fields
+func (_m *IDatabase) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Count provides a mock function with given fields: value
 func (_m *IDatabase) Count(value *int64) repositories.IDatabase {
 	ret := _m.Called(value)
@@ -52,6 +74,29 @@ func (_m *IDatabase) Create(value interface{}) repositories.IDatabase {
 	return r0
 }
 
+// Find provides a mock function with given fields: dest, conds
+func (_m *IDatabase) Find(dest interface{}, conds ...interface{}) repositories.IDatabase {
+	var _ca []interface{}
+	_ca = append(_ca, dest)
+	_ca = append(_ca, conds...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}, ...interface{}) repositories.IDatabase); ok {
+		r0 = rf(dest, conds...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // First provides a mock function with given fields: dest, conds
 func (_m *IDatabase) First(dest interface{}, conds ...interface{}) repositories.IDatabase {
 	var _ca []interface{}
@@ -93,6 +138,46 @@ func (_m *IDatabase) GetError() error {
 	return r0
 }
 
+// Increment provides a mock function with given fields: column, delta
+func (_m *IDatabase) Increment(column string, delta int) repositories.IDatabase {
+	ret := _m.Called(column, delta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Increment")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(string, int) repositories.IDatabase); ok {
+		r0 = rf(column, delta)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Limit provides a mock function with given fields: limit
+func (_m *IDatabase) Limit(limit int) repositories.IDatabase {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Limit")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(int) repositories.IDatabase); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // Model provides a mock function with given fields: value
 func (_m *IDatabase) Model(value interface{}) repositories.IDatabase {
 	ret := _m.Called(value)
@@ -113,6 +198,184 @@ func (_m *IDatabase) Model(value interface{}) repositories.IDatabase {
 	return r0
 }
 
+// Offset provides a mock function with given fields: offset
+func (_m *IDatabase) Offset(offset int) repositories.IDatabase {
+	ret := _m.Called(offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Offset")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(int) repositories.IDatabase); ok {
+		r0 = rf(offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Order provides a mock function with given fields: value
+func (_m *IDatabase) Order(value interface{}) repositories.IDatabase {
+	ret := _m.Called(value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Order")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(interface{}) repositories.IDatabase); ok {
+		r0 = rf(value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *IDatabase) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Returning provides a mock function with given fields: columns
+func (_m *IDatabase) Returning(columns ...string) repositories.IDatabase {
+	_va := make([]interface{}, len(columns))
+	for _i := range columns {
+		_va[_i] = columns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Returning")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(...string) repositories.IDatabase); ok {
+		r0 = rf(columns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// RowsAffected provides a mock function with no fields
+func (_m *IDatabase) RowsAffected() int64 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RowsAffected")
+	}
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// Stats provides a mock function with no fields
+func (_m *IDatabase) Stats() sql.DBStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 sql.DBStats
+	if rf, ok := ret.Get(0).(func() sql.DBStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sql.DBStats)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: column, value
+func (_m *IDatabase) Update(column string, value interface{}) repositories.IDatabase {
+	ret := _m.Called(column, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(string, interface{}) repositories.IDatabase); ok {
+		r0 = rf(column, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// UpdateColumn provides a mock function with given fields: column, value
+func (_m *IDatabase) UpdateColumn(column string, value interface{}) repositories.IDatabase {
+	ret := _m.Called(column, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateColumn")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(string, interface{}) repositories.IDatabase); ok {
+		r0 = rf(column, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// Warmup provides a mock function with given fields: ctx, n
+func (_m *IDatabase) Warmup(ctx context.Context, n int) error {
+	ret := _m.Called(ctx, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Warmup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Where provides a mock function with given fields: query, args
 func (_m *IDatabase) Where(query interface{}, args ...interface{}) repositories.IDatabase {
 	var _ca []interface{}
@@ -136,6 +399,46 @@ func (_m *IDatabase) Where(query interface{}, args ...interface{}) repositories.
 	return r0
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *IDatabase) WithContext(ctx context.Context) repositories.IDatabase {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(context.Context) repositories.IDatabase); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
+// WithSchema provides a mock function with given fields: schema
+func (_m *IDatabase) WithSchema(schema string) repositories.IDatabase {
+	ret := _m.Called(schema)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithSchema")
+	}
+
+	var r0 repositories.IDatabase
+	if rf, ok := ret.Get(0).(func(string) repositories.IDatabase); ok {
+		r0 = rf(schema)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repositories.IDatabase)
+		}
+	}
+
+	return r0
+}
+
 // NewIDatabase creates a new instance of IDatabase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIDatabase(t interface {
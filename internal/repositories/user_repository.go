@@ -1,61 +1,420 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/tenant"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
 	"github.com/google/uuid"
 )
 
+// MaxEmailSearchLimit caps how many users SearchByEmailPrefix returns
+const MaxEmailSearchLimit = 50
+
+// MaxRoleListLimit caps how many users ListUsersByRole returns per page
+const MaxRoleListLimit = 100
+
+// MaxCursorListLimit caps how many users ListUsersAfter returns per page
+const MaxCursorListLimit = 500
+
+// MaxFindByIDs caps how many ids FindByIDs accepts in one call
+const MaxFindByIDs = 100
+
+// ErrUserNotFound is returned by writes that matched no user
+var ErrUserNotFound = errors.New("user not found")
+
+// likeEscaper escapes LIKE wildcards so a search prefix matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type UserRepository struct {
-	DB IDatabase
+	DB     IDatabase
+	ReadDB IDatabase // optional read replica, reads fall back to DB when nil
 }
 
 func NewUserRepository(db IDatabase) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (ur *UserRepository) CreateUser(user *models.User) error {
-	if ur.DB == nil {
+// NewUserRepositoryWithReplica creates a repository that sends read-only queries to readDB
+func NewUserRepositoryWithReplica(db, readDB IDatabase) *UserRepository {
+	return &UserRepository{DB: db, ReadDB: readDB}
+}
+
+// primary returns the connection used for writes, bound to ctx and scoped to the tenant in ctx
+func (ur *UserRepository) primary(ctx context.Context) IDatabase {
+	return forRequest(ctx, ur.DB)
+}
+
+// reader returns the connection used for read-only queries, bound to ctx and scoped to the tenant in ctx
+func (ur *UserRepository) reader(ctx context.Context) IDatabase {
+	if ur.ReadDB != nil {
+		return forRequest(ctx, ur.ReadDB)
+	}
+	return forRequest(ctx, ur.DB)
+}
+
+// forRequest binds db to ctx, so queries honour its cancellation and deadline and the GORM logger
+// sees the request id, and qualifies the tables with the tenant schema from ctx
+// Without a tenant queries stay unqualified and resolve through the default search_path, i.e. public
+func forRequest(ctx context.Context, db IDatabase) IDatabase {
+	if db == nil {
+		return nil
+	}
+	db = db.WithContext(ctx)
+	if id, ok := tenant.FromContext(ctx); ok {
+		return db.WithSchema(id)
+	}
+	return db
+}
+
+func (ur *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	db := ur.primary(ctx)
+	if db == nil {
 		return errors.New("database connection is not initialized")
 	}
 
 	// Generate UUID if not set
 	if user.ID == uuid.Nil {
-		user.ID = uuid.New()
+		user.ID = utils.NewUUID()
 	}
 
-	dbErr := ur.DB.Create(user).GetError()
+	dbErr := db.Create(user).GetError()
 	if dbErr != nil {
-		return fmt.Errorf("cannot create user with email=%s: %w", user.Email, dbErr)
+		return fmt.Errorf("cannot create user with email=%s: %w", utils.MaskEmail(user.Email), dbErr)
 	}
 
 	return nil
 }
 
-func (ur *UserRepository) GetUserByEmail(email string) (*models.User, error) {
-	if ur.DB == nil {
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	db := ur.reader(ctx)
+	if db == nil {
 		return nil, errors.New("database connection is not initialized")
 	}
 
 	var user models.User
-	err := ur.DB.Where("email = ?", email).First(&user).GetError()
+	err := db.Where("email = ?", email).First(&user).GetError()
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (ur *UserRepository) UserExists(email string) (bool, error) {
-	if ur.DB == nil {
+// GetUserByID returns the user with the given id
+func (ur *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	db := ur.reader(ctx)
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var user models.User
+	err := db.Where("id = ?", id).First(&user).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByIDs returns the users with the given ids keyed by id, fetched with a single query
+// Missing and soft-deleted users are absent from the map, more than MaxFindByIDs ids are rejected
+func (ur *UserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.User, error) {
+	db := ur.reader(ctx)
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	if len(ids) > MaxFindByIDs {
+		return nil, fmt.Errorf("cannot find %d users by id: at most %d ids are allowed", len(ids), MaxFindByIDs)
+	}
+	found := make(map[uuid.UUID]models.User, len(ids))
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	var users []models.User
+	err := db.Where("id IN ?", ids).Find(&users).GetError()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find users by id: %w", err)
+	}
+	for _, user := range users {
+		found[user.ID] = user
+	}
+	return found, nil
+}
+
+// UpdatePassword replaces the stored password hash of a user
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).Update("password", passwordHash)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot update password for user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "update_password", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot update password for user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// TouchLastLogin records at as the user's last successful login
+// Only last_login_at is written, updated_at keeps tracking changes to the account itself
+func (ur *UserRepository) TouchLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("last_login_at", at)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot update last login for user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "touch_last_login", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot update last login for user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// RevokeSessions records at as the time before which the user's tokens are no longer valid
+func (ur *UserRepository) RevokeSessions(ctx context.Context, id uuid.UUID, at time.Time) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("sessions_revoked_at", at)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot revoke sessions for user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "revoke_sessions", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot revoke sessions for user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// SessionsRevokedAt returns when the user's sessions were last revoked, nil if never
+// It reads from the primary so a fresh revocation takes effect immediately
+func (ur *UserRepository) SessionsRevokedAt(ctx context.Context, id uuid.UUID) (*time.Time, error) {
+	db := ur.primary(ctx)
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	var user models.User
+	err := db.Where("id = ?", id).First(&user).GetError()
+	if err != nil {
+		return nil, err
+	}
+	return user.SessionsRevokedAt, nil
+}
+
+// IncrementFailedAttempts atomically adds one to the user's failed login counter and returns the new value
+// The increment and the read are a single UPDATE ... RETURNING, so concurrent failures are never lost
+func (ur *UserRepository) IncrementFailedAttempts(ctx context.Context, id uuid.UUID) (int, error) {
+	db := ur.primary(ctx)
+	if db == nil {
+		return 0, errors.New("database connection is not initialized")
+	}
+
+	var user models.User
+	err := db.Model(&user).Returning("failed_attempts").Where("id = ?", id).Increment("failed_attempts", 1).GetError()
+	if err != nil {
+		return 0, fmt.Errorf("cannot increment failed attempts for user id=%s: %w", id, err)
+	}
+	// The counter is at least one after an increment, zero means no row was updated
+	if user.FailedAttempts == 0 {
+		return 0, fmt.Errorf("cannot increment failed attempts for user id=%s: %w", id, ErrUserNotFound)
+	}
+	return user.FailedAttempts, nil
+}
+
+// ResetFailedAttempts clears the user's failed login counter
+func (ur *UserRepository) ResetFailedAttempts(ctx context.Context, id uuid.UUID) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("failed_attempts", 0)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot reset failed attempts for user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "reset_failed_attempts", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot reset failed attempts for user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// SuspendUser records at as the time the user's account was suspended
+func (ur *UserRepository) SuspendUser(ctx context.Context, id uuid.UUID, at time.Time) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("suspended_at", at)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot suspend user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "suspend_user", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot suspend user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// UnsuspendUser clears the user's suspension
+func (ur *UserRepository) UnsuspendUser(ctx context.Context, id uuid.UUID) error {
+	db := ur.primary(ctx)
+	if db == nil {
+		return errors.New("database connection is not initialized")
+	}
+
+	result := db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("suspended_at", nil)
+	if err := result.GetError(); err != nil {
+		return fmt.Errorf("cannot unsuspend user id=%s: %w", id, err)
+	}
+	if err := checkRowsAffected(ctx, "unsuspend_user", id, result.RowsAffected()); err != nil {
+		return fmt.Errorf("cannot unsuspend user id=%s: %w", id, err)
+	}
+	return nil
+}
+
+// checkRowsAffected logs the rows a write to the user affected at DEBUG
+// and reports ErrUserNotFound when it affected none, so no-op writes do not pass silently
+func checkRowsAffected(ctx context.Context, operation string, id uuid.UUID, rows int64) error {
+	slog.DebugContext(ctx, "User write",
+		slog.String("operation", operation),
+		slog.String("user_id", id.String()),
+		slog.Int64("rows_affected", rows),
+	)
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *UserRepository) UserExists(ctx context.Context, email string) (bool, error) {
+	db := ur.reader(ctx)
+	if db == nil {
 		return false, errors.New("database connection is not initialized")
 	}
 
 	var count int64
-	err := ur.DB.Model(&models.User{}).Where("email = ?", email).Count(&count).GetError()
+	err := db.Model(&models.User{}).Where("email = ?", email).Count(&count).GetError()
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// HasUsers reports whether at least one user exists
+// It reads from the primary so a fresh write is never missed
+func (ur *UserRepository) HasUsers(ctx context.Context) (bool, error) {
+	db := ur.primary(ctx)
+	if db == nil {
+		return false, errors.New("database connection is not initialized")
+	}
+
+	var count int64
+	err := db.Model(&models.User{}).Count(&count).GetError()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SearchByEmailPrefix returns users whose email starts with prefix, ignoring case, ordered by email
+// A limit outside 1..MaxEmailSearchLimit is treated as MaxEmailSearchLimit, soft-deleted users are excluded
+func (ur *UserRepository) SearchByEmailPrefix(ctx context.Context, prefix string, limit int) ([]models.User, error) {
+	db := ur.reader(ctx)
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	if limit <= 0 || limit > MaxEmailSearchLimit {
+		limit = MaxEmailSearchLimit
+	}
+	pattern := likeEscaper.Replace(strings.ToLower(prefix)) + "%"
+
+	var users []models.User
+	err := db.
+		Where(`LOWER(email) LIKE ? ESCAPE '\'`, pattern).
+		Order("email").
+		Limit(limit).
+		Find(&users).
+		GetError()
+	if err != nil {
+		return nil, fmt.Errorf("cannot search users by email prefix: %w", err)
+	}
+	return users, nil
+}
+
+// ListUsersByRole returns one page of users holding role, ordered by email
+// Offset pages shift under concurrent inserts, prefer ListUsersAfter for walking large datasets
+// A limit outside 1..MaxRoleListLimit is treated as MaxRoleListLimit, soft-deleted users are excluded
+func (ur *UserRepository) ListUsersByRole(ctx context.Context, role string, limit, offset int) ([]models.User, error) {
+	db := ur.reader(ctx)
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	if limit <= 0 || limit > MaxRoleListLimit {
+		limit = MaxRoleListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var users []models.User
+	err := db.
+		Where("role = ?", role).
+		Order("email").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).
+		GetError()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list users by role: %w", err)
+	}
+	return users, nil
+}
+
+// ListUsersAfter returns the page of users whose id sorts after afterID, ordered by id
+// Pass uuid.Nil for the first page and the returned cursor for the next one, the cursor is uuid.Nil
+// once the last page is reached. Unlike offset pages, inserts never shift or duplicate rows already walked.
+// A limit outside 1..MaxCursorListLimit is treated as MaxCursorListLimit, soft-deleted users are excluded
+func (ur *UserRepository) ListUsersAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]models.User, uuid.UUID, error) {
+	db := ur.reader(ctx)
+	if db == nil {
+		return nil, uuid.Nil, errors.New("database connection is not initialized")
+	}
+
+	if limit <= 0 || limit > MaxCursorListLimit {
+		limit = MaxCursorListLimit
+	}
+
+	var users []models.User
+	err := db.
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&users).
+		GetError()
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("cannot list users after id=%s: %w", afterID, err)
+	}
+
+	next := uuid.Nil
+	if len(users) == limit {
+		next = users[len(users)-1].ID
+	}
+	return users, next, nil
+}
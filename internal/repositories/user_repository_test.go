@@ -1,15 +1,26 @@
 package repositories_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/tenant"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 type UserRepositoryTestSuite struct {
@@ -24,6 +35,7 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 
 func (suite *UserRepositoryTestSuite) SetupTest() {
 	suite.mockDB = new(mocks.IDatabase)
+	suite.mockDB.On("WithContext", mock.Anything).Return(suite.mockDB).Maybe()
 	suite.userRepo = &repositories.UserRepository{DB: suite.mockDB}
 	suite.testUser = &models.User{
 		ID:       uuid.New(),
@@ -86,6 +98,20 @@ func (suite *UserRepositoryTestSuite) TestNewUserRepository_Success() {
 	suite.Equal(mockDB, repo.DB)
 }
 
+func (suite *UserRepositoryTestSuite) TestNewUserRepositoryWithReplica_Success() {
+	// Arrange
+	primary := new(mocks.IDatabase)
+	replica := new(mocks.IDatabase)
+
+	// Act
+	repo := repositories.NewUserRepositoryWithReplica(primary, replica)
+
+	// Assert
+	suite.Require().NotNil(repo)
+	suite.Equal(primary, repo.DB)
+	suite.Equal(replica, repo.ReadDB)
+}
+
 // ===== CREATE USER TESTS =====
 
 func (suite *UserRepositoryTestSuite) TestCreateUser_Success() {
@@ -93,7 +119,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_Success() {
 	suite.mockCreateUser(suite.testUser, nil)
 
 	// Act
-	err := suite.userRepo.CreateUser(suite.testUser)
+	err := suite.userRepo.CreateUser(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -106,7 +132,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_NilDatabase() {
 	repo := &repositories.UserRepository{DB: nil}
 
 	// Act
-	err := repo.CreateUser(suite.testUser)
+	err := repo.CreateUser(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -120,7 +146,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_WithExistingUUID() {
 	suite.mockCreateUser(suite.testUser, nil)
 
 	// Act
-	err := suite.userRepo.CreateUser(suite.testUser)
+	err := suite.userRepo.CreateUser(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -134,7 +160,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser_DatabaseError() {
 	suite.mockCreateUser(suite.testUser, expectedError)
 
 	// Act
-	err := suite.userRepo.CreateUser(suite.testUser)
+	err := suite.userRepo.CreateUser(context.Background(), suite.testUser)
 
 	// Assert
 	suite.Require().Error(err)
@@ -149,7 +175,7 @@ func (suite *UserRepositoryTestSuite) TestGetUserByEmail_Success() {
 	suite.mockGetUserByEmail(suite.testUser.Email, suite.testUser, nil)
 
 	// Act
-	user, err := suite.userRepo.GetUserByEmail(suite.testUser.Email)
+	user, err := suite.userRepo.GetUserByEmail(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -163,7 +189,7 @@ func (suite *UserRepositoryTestSuite) TestGetUserByEmail_NilDatabase() {
 	repo := &repositories.UserRepository{DB: nil}
 
 	// Act
-	user, err := repo.GetUserByEmail(suite.testUser.Email)
+	user, err := repo.GetUserByEmail(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().Error(err)
@@ -176,7 +202,7 @@ func (suite *UserRepositoryTestSuite) TestGetUserByEmail_UserNotFound() {
 	suite.mockGetUserByEmail(suite.testUser.Email, nil, errors.New("record not found"))
 
 	// Act
-	user, err := suite.userRepo.GetUserByEmail(suite.testUser.Email)
+	user, err := suite.userRepo.GetUserByEmail(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().Error(err)
@@ -185,6 +211,121 @@ func (suite *UserRepositoryTestSuite) TestGetUserByEmail_UserNotFound() {
 	suite.mockDB.AssertExpectations(suite.T())
 }
 
+// ===== GET USER BY ID TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestGetUserByID_Success() {
+	// Arrange
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("First", mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.User) = *suite.testUser
+	}).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+
+	// Act
+	user, err := suite.userRepo.GetUserByID(context.Background(), suite.testUser.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID, user.ID)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserByID_UserNotFound() {
+	// Arrange
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("First", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("record not found"))
+
+	// Act
+	user, err := suite.userRepo.GetUserByID(context.Background(), suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(user)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserByID_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	user, err := repo.GetUserByID(context.Background(), suite.testUser.ID)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(user)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
+// ===== UPDATE PASSWORD TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_Success() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(1))
+
+	// Act
+	err := suite.userRepo.UpdatePassword(context.Background(), suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_NoRowsAffected() {
+	// Arrange
+	defaultLogger := slog.Default()
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(nil)
+	suite.mockDB.On("RowsAffected").Return(int64(0))
+
+	// Act
+	err := suite.userRepo.UpdatePassword(context.Background(), suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().ErrorIs(err, repositories.ErrUserNotFound)
+	entry := logs.FindMessage("User write")
+	suite.Require().NotNil(entry)
+	suite.Equal("DEBUG", entry["level"])
+	suite.Equal("update_password", entry["operation"])
+	suite.Equal(suite.testUser.ID.String(), entry["user_id"])
+	suite.Equal(float64(0), entry["rows_affected"])
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_DatabaseError() {
+	// Arrange
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Where", "id = ?", suite.testUser.ID).Return(suite.mockDB)
+	suite.mockDB.On("Update", "password", "new-hash").Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(errors.New("connection lost"))
+
+	// Act
+	err := suite.userRepo.UpdatePassword(context.Background(), suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "cannot update password")
+	suite.Contains(err.Error(), "connection lost")
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdatePassword_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	err := repo.UpdatePassword(context.Background(), suite.testUser.ID, "new-hash")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "database connection is not initialized")
+}
+
 // ===== USER EXISTS TESTS =====
 
 func (suite *UserRepositoryTestSuite) TestUserExists_Success() {
@@ -192,7 +333,7 @@ func (suite *UserRepositoryTestSuite) TestUserExists_Success() {
 	suite.mockCountByEmail(suite.testUser.Email, 1, nil)
 
 	// Act
-	exists, err := suite.userRepo.UserExists(suite.testUser.Email)
+	exists, err := suite.userRepo.UserExists(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -205,7 +346,7 @@ func (suite *UserRepositoryTestSuite) TestUserExists_UserNotFound() {
 	suite.mockCountByEmail(suite.testUser.Email, 0, nil)
 
 	// Act
-	exists, err := suite.userRepo.UserExists(suite.testUser.Email)
+	exists, err := suite.userRepo.UserExists(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().NoError(err)
@@ -219,7 +360,7 @@ func (suite *UserRepositoryTestSuite) TestUserExists_DatabaseError() {
 	suite.mockCountByEmail(suite.testUser.Email, 0, expectedError)
 
 	// Act
-	exists, err := suite.userRepo.UserExists(suite.testUser.Email)
+	exists, err := suite.userRepo.UserExists(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().Error(err)
@@ -233,7 +374,7 @@ func (suite *UserRepositoryTestSuite) TestUserExists_NilDatabase() {
 	repo := &repositories.UserRepository{DB: nil}
 
 	// Act
-	exists, err := repo.UserExists(suite.testUser.Email)
+	exists, err := repo.UserExists(context.Background(), suite.testUser.Email)
 
 	// Assert
 	suite.Require().Error(err)
@@ -241,7 +382,735 @@ func (suite *UserRepositoryTestSuite) TestUserExists_NilDatabase() {
 	suite.Contains(err.Error(), "database connection is not initialized")
 }
 
+// ===== HAS USERS TESTS =====
+
+// mockCountUsers mocks Model(User).Count(&count).GetError()
+func (suite *UserRepositoryTestSuite) mockCountUsers(countValue int64, err error) {
+	suite.mockDB.On("Model", mock.AnythingOfType("*models.User")).Return(suite.mockDB)
+	suite.mockDB.On("Count", mock.AnythingOfType("*int64")).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int64) = countValue
+	}).Return(suite.mockDB)
+	suite.mockDB.On("GetError").Return(err)
+}
+
+func (suite *UserRepositoryTestSuite) TestHasUsers_Empty() {
+	// Arrange
+	suite.mockCountUsers(0, nil)
+
+	// Act
+	hasUsers, err := suite.userRepo.HasUsers(context.Background())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(hasUsers)
+}
+
+func (suite *UserRepositoryTestSuite) TestHasUsers_NotEmpty() {
+	// Arrange
+	suite.mockCountUsers(3, nil)
+
+	// Act
+	hasUsers, err := suite.userRepo.HasUsers(context.Background())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(hasUsers)
+}
+
+func (suite *UserRepositoryTestSuite) TestHasUsers_DatabaseError() {
+	// Arrange
+	suite.mockCountUsers(0, errors.New("database error"))
+
+	// Act
+	hasUsers, err := suite.userRepo.HasUsers(context.Background())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.False(hasUsers)
+}
+
+func (suite *UserRepositoryTestSuite) TestHasUsers_UsesPrimaryWithReplica() {
+	// Arrange
+	replica := new(mocks.IDatabase)
+	suite.userRepo.ReadDB = replica
+	suite.mockCountUsers(1, nil)
+
+	// Act
+	hasUsers, err := suite.userRepo.HasUsers(context.Background())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(hasUsers)
+	replica.AssertNotCalled(suite.T(), "Model", mock.Anything)
+}
+
+// ===== TENANT TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestGetUserByEmail_ScopesToTenant() {
+	// Arrange
+	ctx := tenant.WithID(context.Background(), "acme")
+	suite.mockDB.On("WithSchema", "acme").Return(suite.mockDB)
+	suite.mockGetUserByEmail(suite.testUser.Email, suite.testUser, nil)
+
+	// Act
+	user, err := suite.userRepo.GetUserByEmail(ctx, suite.testUser.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, user.Email)
+}
+
+// ===== CONTEXT TESTS =====
+
+func (suite *UserRepositoryTestSuite) TestGetUserByEmail_BindsRequestContext() {
+	// Arrange
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	unbound := new(mocks.IDatabase)
+	unbound.On("WithContext", ctx).Return(suite.mockDB).Once()
+	suite.userRepo = repositories.NewUserRepository(unbound)
+	suite.mockGetUserByEmail(suite.testUser.Email, suite.testUser, nil)
+
+	// Act
+	user, err := suite.userRepo.GetUserByEmail(ctx, suite.testUser.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, user.Email)
+	unbound.AssertExpectations(suite.T())
+}
+
+// ===== READ REPLICA TESTS =====
+
+// useReplica routes reads to suite.mockDB and returns the primary, which must stay untouched
+func (suite *UserRepositoryTestSuite) useReplica() *mocks.IDatabase {
+	primary := new(mocks.IDatabase)
+	suite.userRepo = repositories.NewUserRepositoryWithReplica(primary, suite.mockDB)
+	return primary
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserByEmail_UsesReplica() {
+	// Arrange
+	primary := suite.useReplica()
+	suite.mockGetUserByEmail(suite.testUser.Email, suite.testUser, nil)
+
+	// Act
+	user, err := suite.userRepo.GetUserByEmail(context.Background(), suite.testUser.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, user.Email)
+	primary.AssertNotCalled(suite.T(), "Where", mock.Anything, mock.Anything)
+}
+
+func (suite *UserRepositoryTestSuite) TestUserExists_UsesReplica() {
+	// Arrange
+	primary := suite.useReplica()
+	suite.mockCountByEmail(suite.testUser.Email, 1, nil)
+
+	// Act
+	exists, err := suite.userRepo.UserExists(context.Background(), suite.testUser.Email)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(exists)
+	primary.AssertNotCalled(suite.T(), "Model", mock.Anything)
+}
+
+func (suite *UserRepositoryTestSuite) TestCreateUser_UsesPrimaryWithReplica() {
+	// Arrange
+	replica := new(mocks.IDatabase)
+	suite.userRepo = repositories.NewUserRepositoryWithReplica(suite.mockDB, replica)
+	suite.mockCreateUser(suite.testUser, nil)
+
+	// Act
+	err := suite.userRepo.CreateUser(context.Background(), suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	replica.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
 // Run tests
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
+
+// ===== SQLITE-BACKED TESTS =====
+
+// UserRepositorySQLiteTestSuite runs repository queries against a real in-memory database
+type UserRepositorySQLiteTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	userRepo *repositories.UserRepository
+}
+
+func (suite *UserRepositorySQLiteTestSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&models.User{}))
+	// A single connection keeps the in-memory database and attached schemas shared
+	sqlDB, err := db.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	suite.db = db
+	suite.userRepo = repositories.NewUserRepository(repositories.NewGormAdapterFromDB(db))
+}
+
+// seedUsers creates one user per email
+func (suite *UserRepositorySQLiteTestSuite) seedUsers(emails ...string) {
+	for _, email := range emails {
+		suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), &models.User{Email: email, Password: "hash"}))
+	}
+}
+
+// searchEmails returns the emails SearchByEmailPrefix finds
+func (suite *UserRepositorySQLiteTestSuite) searchEmails(prefix string, limit int) []string {
+	users, err := suite.userRepo.SearchByEmailPrefix(context.Background(), prefix, limit)
+	suite.Require().NoError(err)
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_MatchesPrefix() {
+	// Arrange
+	suite.seedUsers("alice@example.com", "albert@example.com", "bob@example.com", "xalice@example.com")
+
+	// Act
+	emails := suite.searchEmails("al", 10)
+
+	// Assert
+	suite.Equal([]string{"albert@example.com", "alice@example.com"}, emails)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_CaseInsensitive() {
+	// Arrange
+	suite.seedUsers("Alice@Example.com", "bob@example.com")
+
+	// Act
+	emails := suite.searchEmails("ALI", 10)
+
+	// Assert
+	suite.Equal([]string{"Alice@Example.com"}, emails)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_WildcardsMatchLiterally() {
+	// Arrange
+	suite.seedUsers("a_b@example.com", "axb@example.com", "100%@example.com")
+
+	// Act
+	underscore := suite.searchEmails("a_", 10)
+	percent := suite.searchEmails("%", 10)
+
+	// Assert
+	suite.Equal([]string{"a_b@example.com"}, underscore)
+	suite.Empty(percent)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_LimitCap() {
+	// Arrange
+	for i := 0; i < repositories.MaxEmailSearchLimit+5; i++ {
+		suite.seedUsers(fmt.Sprintf("user%03d@example.com", i))
+	}
+
+	// Act
+	limited := suite.searchEmails("user", 3)
+	capped := suite.searchEmails("user", 1000)
+	unset := suite.searchEmails("user", 0)
+
+	// Assert
+	suite.Equal([]string{"user000@example.com", "user001@example.com", "user002@example.com"}, limited)
+	suite.Len(capped, repositories.MaxEmailSearchLimit)
+	suite.Len(unset, repositories.MaxEmailSearchLimit)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_ExcludesSoftDeleted() {
+	// Arrange
+	suite.seedUsers("carol@example.com", "carl@example.com")
+	suite.Require().NoError(suite.db.Where("email = ?", "carl@example.com").Delete(&models.User{}).Error)
+
+	// Act
+	emails := suite.searchEmails("car", 10)
+
+	// Assert
+	suite.Equal([]string{"carol@example.com"}, emails)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSearchByEmailPrefix_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	users, err := repo.SearchByEmailPrefix(context.Background(), "a", 10)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(users)
+}
+
+// seedUsersWithRole creates one user with role per email
+func (suite *UserRepositorySQLiteTestSuite) seedUsersWithRole(role string, emails ...string) {
+	for _, email := range emails {
+		suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), &models.User{Email: email, Password: "hash", Role: role}))
+	}
+}
+
+// roleEmails returns the emails ListUsersByRole finds
+func (suite *UserRepositorySQLiteTestSuite) roleEmails(role string, limit, offset int) []string {
+	users, err := suite.userRepo.ListUsersByRole(context.Background(), role, limit, offset)
+	suite.Require().NoError(err)
+	emails := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = user.Email
+	}
+	return emails
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersByRole_MatchesRole() {
+	// Arrange
+	suite.seedUsersWithRole(models.RoleAdmin, "root@example.com", "admin@example.com")
+	suite.seedUsersWithRole(models.RoleUser, "alice@example.com", "bob@example.com")
+
+	// Act
+	admins := suite.roleEmails(models.RoleAdmin, 10, 0)
+	users := suite.roleEmails(models.RoleUser, 10, 0)
+	unknown := suite.roleEmails("auditor", 10, 0)
+
+	// Assert
+	suite.Equal([]string{"admin@example.com", "root@example.com"}, admins)
+	suite.Equal([]string{"alice@example.com", "bob@example.com"}, users)
+	suite.Empty(unknown)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersByRole_Paginates() {
+	// Arrange
+	suite.seedUsersWithRole(models.RoleAdmin, "a1@example.com", "a2@example.com", "a3@example.com", "a4@example.com", "a5@example.com")
+	suite.seedUsersWithRole(models.RoleUser, "a0@example.com")
+
+	// Act
+	first := suite.roleEmails(models.RoleAdmin, 2, 0)
+	second := suite.roleEmails(models.RoleAdmin, 2, 2)
+	last := suite.roleEmails(models.RoleAdmin, 2, 4)
+	past := suite.roleEmails(models.RoleAdmin, 2, 6)
+	negative := suite.roleEmails(models.RoleAdmin, 2, -1)
+
+	// Assert
+	suite.Equal([]string{"a1@example.com", "a2@example.com"}, first)
+	suite.Equal([]string{"a3@example.com", "a4@example.com"}, second)
+	suite.Equal([]string{"a5@example.com"}, last)
+	suite.Empty(past)
+	suite.Equal(first, negative)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersByRole_LimitCap() {
+	// Arrange
+	for i := 0; i < repositories.MaxRoleListLimit+5; i++ {
+		suite.seedUsersWithRole(models.RoleUser, fmt.Sprintf("user%03d@example.com", i))
+	}
+
+	// Act
+	capped := suite.roleEmails(models.RoleUser, 1000, 0)
+	unset := suite.roleEmails(models.RoleUser, 0, 0)
+
+	// Assert
+	suite.Len(capped, repositories.MaxRoleListLimit)
+	suite.Len(unset, repositories.MaxRoleListLimit)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersByRole_ExcludesSoftDeleted() {
+	// Arrange
+	suite.seedUsersWithRole(models.RoleAdmin, "kept@example.com", "removed@example.com")
+	suite.Require().NoError(suite.db.Where("email = ?", "removed@example.com").Delete(&models.User{}).Error)
+
+	// Act
+	emails := suite.roleEmails(models.RoleAdmin, 10, 0)
+
+	// Assert
+	suite.Equal([]string{"kept@example.com"}, emails)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersByRole_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	users, err := repo.ListUsersByRole(context.Background(), models.RoleAdmin, 10, 0)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(users)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersAfter_WalksAllPages() {
+	// Arrange
+	for i := 0; i < 23; i++ {
+		suite.seedUsers(fmt.Sprintf("user%02d@example.com", i))
+	}
+
+	// Act
+	seen := make(map[uuid.UUID]int)
+	var walked []uuid.UUID
+	cursor := uuid.Nil
+	for pages := 0; ; pages++ {
+		suite.Require().Less(pages, 10, "cursor walk did not terminate")
+		users, next, err := suite.userRepo.ListUsersAfter(context.Background(), cursor, 5)
+		suite.Require().NoError(err)
+		for _, user := range users {
+			seen[user.ID]++
+			walked = append(walked, user.ID)
+		}
+		if next == uuid.Nil {
+			break
+		}
+		cursor = next
+	}
+
+	// Assert
+	var all []models.User
+	suite.Require().NoError(suite.db.Order("id").Find(&all).Error)
+	suite.Len(walked, len(all), "every user must be visited")
+	for _, user := range all {
+		suite.Equal(1, seen[user.ID], "user %s must be visited exactly once", user.ID)
+	}
+	for i := 1; i < len(walked); i++ {
+		suite.Less(walked[i-1].String(), walked[i].String(), "pages must be ordered by id")
+	}
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersAfter_InsertsDoNotShiftPages() {
+	// Arrange
+	suite.seedUsers("a@example.com", "b@example.com", "c@example.com", "d@example.com")
+	first, cursor, err := suite.userRepo.ListUsersAfter(context.Background(), uuid.Nil, 2)
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(uuid.Nil, cursor)
+	// A user sorting before the cursor must not reappear in or push rows into later pages
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), &models.User{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Email: "early@example.com", Password: "hash"}))
+
+	// Act
+	rest, _, err := suite.userRepo.ListUsersAfter(context.Background(), cursor, 10)
+
+	// Assert
+	suite.Require().NoError(err)
+	for _, user := range rest {
+		suite.Greater(user.ID.String(), cursor.String())
+		for _, earlier := range first {
+			suite.NotEqual(earlier.ID, user.ID)
+		}
+	}
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersAfter_LastPageHasNoCursor() {
+	// Arrange
+	suite.seedUsers("a@example.com", "b@example.com")
+
+	// Act
+	users, next, err := suite.userRepo.ListUsersAfter(context.Background(), uuid.Nil, 5)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Len(users, 2)
+	suite.Equal(uuid.Nil, next)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestListUsersAfter_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	users, next, err := repo.ListUsersAfter(context.Background(), uuid.Nil, 10)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(users)
+	suite.Equal(uuid.Nil, next)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestFindByIDs_SkipsMissing() {
+	// Arrange
+	alice := &models.User{Email: "alice@example.com", Password: "hash"}
+	bob := &models.User{Email: "bob@example.com", Password: "hash"}
+	removed := &models.User{Email: "removed@example.com", Password: "hash"}
+	for _, user := range []*models.User{alice, bob, removed} {
+		suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	}
+	suite.Require().NoError(suite.db.Delete(&models.User{}, "id = ?", removed.ID).Error)
+	missing := uuid.New()
+
+	// Act
+	users, err := suite.userRepo.FindByIDs(context.Background(), []uuid.UUID{alice.ID, missing, bob.ID, removed.ID})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Len(users, 2)
+	suite.Equal("alice@example.com", users[alice.ID].Email)
+	suite.Equal("bob@example.com", users[bob.ID].Email)
+	suite.NotContains(users, missing)
+	suite.NotContains(users, removed.ID)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestFindByIDs_EmptyInput() {
+	// Act
+	users, err := suite.userRepo.FindByIDs(context.Background(), nil)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotNil(users)
+	suite.Empty(users)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestFindByIDs_TooManyIDs() {
+	// Arrange
+	ids := make([]uuid.UUID, repositories.MaxFindByIDs+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	// Act
+	users, err := suite.userRepo.FindByIDs(context.Background(), ids)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), fmt.Sprintf("at most %d ids", repositories.MaxFindByIDs))
+	suite.Nil(users)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestFindByIDs_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	users, err := repo.FindByIDs(context.Background(), []uuid.UUID{uuid.New()})
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(users)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestTouchLastLogin_SetsTimestamp() {
+	// Arrange
+	user := &models.User{Email: "john@example.com", Password: "hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	loginTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	err := suite.userRepo.TouchLastLogin(context.Background(), user.ID, loginTime)
+
+	// Assert
+	suite.Require().NoError(err)
+	stored, err := suite.userRepo.GetUserByID(context.Background(), user.ID)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored.LastLoginAt)
+	suite.True(loginTime.Equal(*stored.LastLoginAt))
+	suite.True(user.UpdatedAt.Equal(stored.UpdatedAt), "updated_at must not change")
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestTouchLastLogin_NilDatabase() {
+	// Arrange
+	repo := &repositories.UserRepository{DB: nil}
+
+	// Act
+	err := repo.TouchLastLogin(context.Background(), uuid.New(), time.Now())
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestRevokeSessions_SetsTimestamp() {
+	// Arrange
+	user := &models.User{Email: "john@example.com", Password: "hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	neverRevoked, err := suite.userRepo.SessionsRevokedAt(context.Background(), user.ID)
+	suite.Require().NoError(err)
+	revokedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	err = suite.userRepo.RevokeSessions(context.Background(), user.ID, revokedAt)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Nil(neverRevoked)
+	stored, err := suite.userRepo.SessionsRevokedAt(context.Background(), user.ID)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored)
+	suite.True(revokedAt.Equal(*stored))
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestWrites_UnknownUser() {
+	// Arrange
+	id := uuid.New()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	writes := map[string]func() error{
+		"UpdatePassword":      func() error { return suite.userRepo.UpdatePassword(context.Background(), id, "new-hash") },
+		"TouchLastLogin":      func() error { return suite.userRepo.TouchLastLogin(context.Background(), id, at) },
+		"RevokeSessions":      func() error { return suite.userRepo.RevokeSessions(context.Background(), id, at) },
+		"ResetFailedAttempts": func() error { return suite.userRepo.ResetFailedAttempts(context.Background(), id) },
+		"SuspendUser":         func() error { return suite.userRepo.SuspendUser(context.Background(), id, at) },
+		"UnsuspendUser":       func() error { return suite.userRepo.UnsuspendUser(context.Background(), id) },
+	}
+
+	for name, write := range writes {
+		// Act
+		err := write()
+
+		// Assert
+		suite.Require().ErrorIs(err, repositories.ErrUserNotFound, name)
+	}
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSessionsRevokedAt_UnknownUser() {
+	// Act
+	revokedAt, err := suite.userRepo.SessionsRevokedAt(context.Background(), uuid.New())
+
+	// Assert
+	suite.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+	suite.Nil(revokedAt)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestIncrementFailedAttempts_Concurrent() {
+	// Arrange
+	user := &models.User{Email: "john@example.com", Password: "hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	const calls = 50
+	var wg sync.WaitGroup
+	results := make(chan int, calls)
+
+	// Act
+	for range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := suite.userRepo.IncrementFailedAttempts(context.Background(), user.ID)
+			suite.NoError(err)
+			results <- count
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// Assert
+	seen := make(map[int]bool, calls)
+	for count := range results {
+		seen[count] = true
+	}
+	suite.Len(seen, calls, "every increment must return a distinct value")
+	stored, err := suite.userRepo.GetUserByID(context.Background(), user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(calls, stored.FailedAttempts)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestResetFailedAttempts() {
+	// Arrange
+	user := &models.User{Email: "john@example.com", Password: "hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	_, err := suite.userRepo.IncrementFailedAttempts(context.Background(), user.ID)
+	suite.Require().NoError(err)
+
+	// Act
+	err = suite.userRepo.ResetFailedAttempts(context.Background(), user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	count, err := suite.userRepo.IncrementFailedAttempts(context.Background(), user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(1, count)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestIncrementFailedAttempts_UnknownUser() {
+	// Act
+	count, err := suite.userRepo.IncrementFailedAttempts(context.Background(), uuid.New())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "user not found")
+	suite.Zero(count)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestSuspendAndUnsuspendUser() {
+	// Arrange
+	user := &models.User{Email: "john@example.com", Password: "hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(context.Background(), user))
+	suspendedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	suspendErr := suite.userRepo.SuspendUser(context.Background(), user.ID, suspendedAt)
+	suspended, _ := suite.userRepo.GetUserByID(context.Background(), user.ID)
+	unsuspendErr := suite.userRepo.UnsuspendUser(context.Background(), user.ID)
+	active, _ := suite.userRepo.GetUserByID(context.Background(), user.ID)
+
+	// Assert
+	suite.Require().NoError(suspendErr)
+	suite.Require().NoError(unsuspendErr)
+	suite.Require().NotNil(suspended.SuspendedAt)
+	suite.True(suspendedAt.Equal(*suspended.SuspendedAt))
+	suite.Nil(active.SuspendedAt)
+}
+
+// attachTenantSchema creates a users table in a separate schema named id
+func (suite *UserRepositorySQLiteTestSuite) attachTenantSchema(id string) {
+	suite.Require().NoError(suite.db.Exec("ATTACH DATABASE ':memory:' AS " + id).Error)
+	var ddl string
+	suite.Require().NoError(suite.db.Raw("SELECT sql FROM main.sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&ddl).Error)
+	suite.Require().NoError(suite.db.Exec(strings.Replace(ddl, "`users`", id+".`users`", 1)).Error)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestTenant_QueriesTargetTenantSchema() {
+	// Arrange
+	suite.attachTenantSchema("acme")
+	acmeCtx := tenant.WithID(context.Background(), "acme")
+	suite.seedUsers("shared@example.com")
+
+	// Act
+	createErr := suite.userRepo.CreateUser(acmeCtx, &models.User{Email: "tenant@example.com", Password: "hash"})
+	tenantUser, tenantErr := suite.userRepo.GetUserByEmail(acmeCtx, "tenant@example.com")
+	sharedInTenant, _ := suite.userRepo.UserExists(acmeCtx, "shared@example.com")
+	tenantInDefault, _ := suite.userRepo.UserExists(context.Background(), "tenant@example.com")
+
+	// Assert
+	suite.Require().NoError(createErr)
+	suite.Require().NoError(tenantErr)
+	suite.Equal("tenant@example.com", tenantUser.Email)
+	suite.False(sharedInTenant)
+	suite.False(tenantInDefault)
+	var count int64
+	suite.Require().NoError(suite.db.Table("acme.users").Count(&count).Error)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestTenant_UpdateTargetsTenantSchema() {
+	// Arrange
+	suite.attachTenantSchema("acme")
+	acmeCtx := tenant.WithID(context.Background(), "acme")
+	user := &models.User{Email: "tenant@example.com", Password: "old-hash"}
+	suite.Require().NoError(suite.userRepo.CreateUser(acmeCtx, user))
+
+	// Act
+	err := suite.userRepo.UpdatePassword(acmeCtx, user.ID, "new-hash")
+
+	// Assert
+	suite.Require().NoError(err)
+	updated, err := suite.userRepo.GetUserByID(acmeCtx, user.ID)
+	suite.Require().NoError(err)
+	suite.Equal("new-hash", updated.Password)
+}
+
+func (suite *UserRepositorySQLiteTestSuite) TestTenant_NoTenantUsesDefaultSchema() {
+	// Arrange
+	suite.attachTenantSchema("acme")
+	suite.seedUsers("shared@example.com")
+
+	// Act
+	hasUsers, err := suite.userRepo.HasUsers(context.Background())
+	tenantHasUsers, tenantErr := suite.userRepo.HasUsers(tenant.WithID(context.Background(), "acme"))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NoError(tenantErr)
+	suite.True(hasUsers)
+	suite.False(tenantHasUsers)
+}
+
+func TestUserRepositorySQLiteTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRepositorySQLiteTestSuite))
+}
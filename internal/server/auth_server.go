@@ -2,9 +2,13 @@ package server
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AuthServer struct {
@@ -27,16 +31,14 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *authpb.TokenRequest
 		}, nil
 	}
 
-	userIDStr, ok := claims["user_id"].(string)
-	if !ok {
+	if claims.UserID == "" {
 		return &authpb.UserResponse{
 			Valid: false,
 			Error: "Invalid user ID in token",
 		}, nil
 	}
 
-	email, ok := claims["email"].(string)
-	if !ok {
+	if claims.Email == "" {
 		return &authpb.UserResponse{
 			Valid: false,
 			Error: "Invalid email in token",
@@ -44,15 +46,17 @@ func (s *AuthServer) ValidateToken(ctx context.Context, req *authpb.TokenRequest
 	}
 
 	return &authpb.UserResponse{
-		UserId: userIDStr,
-		Email:  email,
+		UserId: claims.UserID,
+		Email:  claims.Email,
 		Valid:  true,
 	}, nil
 }
 
 func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
 	user, err := s.AuthService.Register(ctx, req.Email, req.Password)
-
+	if errors.Is(err, services.ErrInvalidArgument) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return &authpb.RegisterResponse{
 			Success: false,
@@ -67,11 +71,25 @@ func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest)
 		Message: "User created successfully",
 	}
 
+	// The user is stored by now, so a token failure must not turn into a failed registration
+	if req.IssueTokens {
+		token, err := s.AuthService.GenerateJWTToken(user)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to issue token after registration", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+			response.Message = "User created successfully, log in to obtain a token"
+		} else {
+			response.Token = token
+		}
+	}
+
 	return response, nil
 }
 
 func (s *AuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
 	token, user, err := s.AuthService.Login(ctx, req.Email, req.Password)
+	if errors.Is(err, services.ErrInvalidArgument) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return &authpb.LoginResponse{
 			Success: false,
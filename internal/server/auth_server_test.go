@@ -3,15 +3,21 @@ package server_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
 	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
 	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AuthServerTestSuite struct {
@@ -47,9 +53,9 @@ func (suite *AuthServerTestSuite) TearDownTest() {
 func (suite *AuthServerTestSuite) TestValidateToken_Success() {
 	// Arrange
 	req := &authpb.TokenRequest{Token: suite.token}
-	expectedClaims := jwt.MapClaims{
-		"user_id": "test-user-id",
-		"email":   suite.email,
+	expectedClaims := &services.Claims{
+		UserID: "test-user-id",
+		Email:  suite.email,
 	}
 	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
 
@@ -86,9 +92,8 @@ func (suite *AuthServerTestSuite) TestValidateToken_InvalidToken() {
 func (suite *AuthServerTestSuite) TestValidateToken_InvalidUserID() {
 	// Arrange
 	req := &authpb.TokenRequest{Token: suite.token}
-	expectedClaims := jwt.MapClaims{
-		"user_id": 123, // Invalid type
-		"email":   suite.email,
+	expectedClaims := &services.Claims{
+		Email: suite.email, // user_id missing
 	}
 	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
 
@@ -107,9 +112,8 @@ func (suite *AuthServerTestSuite) TestValidateToken_InvalidUserID() {
 func (suite *AuthServerTestSuite) TestValidateToken_InvalidEmail() {
 	// Arrange
 	req := &authpb.TokenRequest{Token: suite.token}
-	expectedClaims := jwt.MapClaims{
-		"user_id": "test-user-id",
-		"email":   123, // Invalid type
+	expectedClaims := &services.Claims{
+		UserID: "test-user-id", // email missing
 	}
 	suite.mockAuthService.On("ValidateToken", suite.ctx, suite.token).Return(expectedClaims, nil)
 
@@ -153,6 +157,64 @@ func (suite *AuthServerTestSuite) TestRegister_Success() {
 	suite.Empty(response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestRegister_WithTokens() {
+	// Arrange
+	userRepo := repositoryMocks.NewIUserRepository(suite.T())
+	userRepo.On("UserExists", mock.Anything, suite.email).Return(false, nil)
+	userRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.User).ID = uuid.New()
+	}).Return(nil)
+	authService := services.NewAuthService(userRepo, nil, &config.Config{JWTSecret: "test-secret"})
+	authServer := server.NewAuthServer(authService)
+	req := &authpb.RegisterRequest{Email: suite.email, Password: suite.password, IssueTokens: true}
+
+	// Act
+	response, err := authServer.Register(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().True(response.Success)
+	suite.Require().NotEmpty(response.Token)
+	userRepo.On("SessionsRevokedAt", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(nil, nil)
+	claims, err := authService.ValidateToken(suite.ctx, response.Token)
+	suite.Require().NoError(err)
+	suite.Equal(response.UserId, claims.UserID)
+	suite.Equal(suite.email, claims.Email)
+}
+
+func (suite *AuthServerTestSuite) TestRegister_WithoutTokens() {
+	// Arrange
+	req := &authpb.RegisterRequest{Email: suite.email, Password: suite.password}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(&models.User{ID: uuid.New(), Email: suite.email}, nil)
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Empty(response.Token)
+	suite.mockAuthService.AssertNotCalled(suite.T(), "GenerateJWTToken", mock.Anything)
+}
+
+func (suite *AuthServerTestSuite) TestRegister_TokenFailureKeepsRegistration() {
+	// Arrange
+	req := &authpb.RegisterRequest{Email: suite.email, Password: suite.password, IssueTokens: true}
+	user := &models.User{ID: uuid.New(), Email: suite.email}
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(user, nil)
+	suite.mockAuthService.On("GenerateJWTToken", user).Return("", errors.New("JWT secret is not configured"))
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, req)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(response.Success)
+	suite.Equal(user.ID.String(), response.UserId)
+	suite.Empty(response.Token)
+	suite.Contains(response.Message, "log in")
+}
+
 func (suite *AuthServerTestSuite) TestRegister_Error() {
 	// Arrange
 	req := &authpb.RegisterRequest{
@@ -175,6 +237,21 @@ func (suite *AuthServerTestSuite) TestRegister_Error() {
 	suite.Equal("user already exists", response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestRegister_InvalidArgument() {
+	// Arrange
+	req := &authpb.RegisterRequest{Email: suite.email, Password: suite.password}
+	expectedError := fmt.Errorf("%w: password must be at most 1024 bytes", services.ErrInvalidArgument)
+	suite.mockAuthService.On("Register", suite.ctx, suite.email, suite.password).Return(nil, expectedError)
+
+	// Act
+	response, err := suite.authServer.Register(suite.ctx, req)
+
+	// Assert
+	suite.Nil(response)
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+	suite.Contains(status.Convert(err).Message(), "password must be at most 1024 bytes")
+}
+
 // ===== LOGIN TESTS =====
 
 func (suite *AuthServerTestSuite) TestLogin_Success() {
@@ -228,6 +305,20 @@ func (suite *AuthServerTestSuite) TestLogin_Error() {
 	suite.Equal("invalid credentials", response.Error)
 }
 
+func (suite *AuthServerTestSuite) TestLogin_InvalidArgument() {
+	// Arrange
+	req := &authpb.LoginRequest{Email: suite.email, Password: suite.password}
+	expectedError := fmt.Errorf("%w: email must be at most 254 bytes", services.ErrInvalidArgument)
+	suite.mockAuthService.On("Login", suite.ctx, suite.email, suite.password).Return("", nil, expectedError)
+
+	// Act
+	response, err := suite.authServer.Login(suite.ctx, req)
+
+	// Assert
+	suite.Nil(response)
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+}
+
 // Run tests
 func TestAuthServerTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthServerTestSuite))
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"google.golang.org/grpc"
+)
+
+// Interceptor stage names, as reported by InterceptorNames
+const (
+	StageRecovery      = "recovery"
+	StageVersion       = "version"
+	StageEnrich        = "enrich"
+	StageLogging       = "logging"
+	StageMaintenance   = "maintenance"
+	StageMetadataLimit = "metadata_limit"
+	StageRequestLimit  = "request_limit"
+	StageTenant        = "tenant"
+	StageAuth          = "auth"
+)
+
+// interceptorStage is a named entry of the unary interceptor chain
+type interceptorStage struct {
+	name        string
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// interceptorStages lists the enabled interceptors, outermost first
+//
+// The order is fixed: recovery, version, metadata_limit, request_limit, enrich, logging, maintenance, tenant, auth.
+// Recovery wraps everything so a panic anywhere in the chain becomes an Internal error,
+// the version header is set next so every response carries it, rejections included.
+// The size limits follow so oversized metadata and requests are rejected before enrich copies metadata
+// into the log context and logging writes it, metadata limiting first, then the per-method request size limit.
+// Enrich runs before logging so the completion log carries the caller address,
+// logging sits outside the remaining guards so requests they reject are still logged,
+// and those guards run before auth so cheap rejections happen first.
+// Maintenance mode is the first of them and only present when a maintenance switch is given,
+// tenant selection comes next, after the tenant header has already been size-checked.
+// Auth is innermost and only present when an auth service is given, cfg.AuthPublicMethods are served without a token.
+func interceptorStages(cfg *config.Config, authService services.IAuthService, maintenance *Maintenance) []interceptorStage {
+	stages := []interceptorStage{
+		{name: StageRecovery, interceptor: RecoveryUnaryInterceptor()},
+		{name: StageVersion, interceptor: VersionUnaryInterceptor(BuildVersion())},
+	}
+
+	if cfg.MaxMetadataBytes > 0 {
+		stages = append(stages, interceptorStage{name: StageMetadataLimit, interceptor: MetadataLimitUnaryInterceptor(cfg.MaxMetadataBytes)})
+	}
+	if len(cfg.MaxRequestBytes) > 0 {
+		stages = append(stages, interceptorStage{name: StageRequestLimit, interceptor: MaxRequestSizeUnaryInterceptor(cfg.MaxRequestBytes)})
+	}
+	stages = append(stages,
+		interceptorStage{name: StageEnrich, interceptor: EnrichUnaryInterceptor()},
+		interceptorStage{name: StageLogging, interceptor: LoggingUnaryInterceptor(cfg.SlowRPCThreshold)},
+	)
+	if maintenance != nil {
+		stages = append(stages, interceptorStage{name: StageMaintenance, interceptor: MaintenanceUnaryInterceptor(maintenance)})
+	}
+	if cfg.MultiTenant {
+		stages = append(stages, interceptorStage{name: StageTenant, interceptor: TenantUnaryInterceptor(cfg.TenantSchemas)})
+	}
+	if authService != nil {
+		stages = append(stages, interceptorStage{name: StageAuth, interceptor: AuthUnaryInterceptor(authService, cfg.AuthPublicMethods)})
+	}
+
+	return stages
+}
+
+// BuildInterceptors assembles the unary interceptor chain for cfg in the documented order
+// The auth stage is added when authService is non-nil, the maintenance stage when maintenance is non-nil
+// The result is meant for grpc.ChainUnaryInterceptor
+func BuildInterceptors(cfg *config.Config, authService services.IAuthService, maintenance *Maintenance) []grpc.UnaryServerInterceptor {
+	stages := interceptorStages(cfg, authService, maintenance)
+	interceptors := make([]grpc.UnaryServerInterceptor, len(stages))
+	for i, stage := range stages {
+		interceptors[i] = stage.interceptor
+	}
+	return interceptors
+}
+
+// InterceptorNames returns the stage names BuildInterceptors produces for cfg, outermost first
+func InterceptorNames(cfg *config.Config, authService services.IAuthService, maintenance *Maintenance) []string {
+	stages := interceptorStages(cfg, authService, maintenance)
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.name
+	}
+	return names
+}
+
+// BuildStreamInterceptors assembles the stream interceptor chain for cfg
+// It mirrors BuildInterceptors for the stages that have a streaming variant: recovery, metadata_limit,
+// request_limit, logging, maintenance, tenant and auth, so streams are guarded and authenticated like unary RPCs
+func BuildStreamInterceptors(cfg *config.Config, authService services.IAuthService, maintenance *Maintenance) []grpc.StreamServerInterceptor {
+	interceptors := []grpc.StreamServerInterceptor{
+		RecoveryStreamInterceptor(),
+	}
+
+	if cfg.MaxMetadataBytes > 0 {
+		interceptors = append(interceptors, MetadataLimitStreamInterceptor(cfg.MaxMetadataBytes))
+	}
+	if len(cfg.MaxRequestBytes) > 0 {
+		interceptors = append(interceptors, MaxRequestSizeStreamInterceptor(cfg.MaxRequestBytes))
+	}
+	interceptors = append(interceptors, LoggingStreamInterceptor(cfg.SlowRPCThreshold))
+	if maintenance != nil {
+		interceptors = append(interceptors, MaintenanceStreamInterceptor(maintenance))
+	}
+	if cfg.MultiTenant {
+		interceptors = append(interceptors, TenantStreamInterceptor(cfg.TenantSchemas))
+	}
+	if authService != nil {
+		interceptors = append(interceptors, AuthStreamInterceptor(authService, cfg.AuthPublicMethods))
+	}
+
+	return interceptors
+}
@@ -0,0 +1,219 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type ChainTestSuite struct {
+	suite.Suite
+	logs          *logtest.CapturedLogs
+	defaultLogger *slog.Logger
+}
+
+func (suite *ChainTestSuite) SetupTest() {
+	suite.defaultLogger = slog.Default()
+	var logger *slog.Logger
+	logger, suite.logs = logtest.NewTestLogger()
+	slog.SetDefault(logger)
+}
+
+func (suite *ChainTestSuite) TearDownTest() {
+	slog.SetDefault(suite.defaultLogger)
+}
+
+// chainUnary composes interceptors the same way grpc.ChainUnaryInterceptor does
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+// ===== ORDER TESTS =====
+
+func (suite *ChainTestSuite) TestInterceptorNames_AllEnabled() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192, SlowRPCThreshold: time.Second}
+
+	// Act
+	names := server.InterceptorNames(cfg, nil, nil)
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageMetadataLimit, server.StageEnrich, server.StageLogging}, names)
+	suite.Len(server.BuildInterceptors(cfg, nil, nil), len(names))
+}
+
+func (suite *ChainTestSuite) TestInterceptorNames_MultiTenant() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192, MultiTenant: true}
+
+	// Act
+	names := server.InterceptorNames(cfg, nil, nil)
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageMetadataLimit, server.StageEnrich, server.StageLogging, server.StageTenant}, names)
+}
+
+func (suite *ChainTestSuite) TestInterceptorNames_AuthIsInnermost() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192, MultiTenant: true}
+
+	// Act
+	names := server.InterceptorNames(cfg, new(mocks.IAuthService), nil)
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageMetadataLimit, server.StageEnrich, server.StageLogging, server.StageTenant, server.StageAuth}, names)
+}
+
+func (suite *ChainTestSuite) TestInterceptorNames_MetadataLimitDisabled() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 0}
+
+	// Act
+	names := server.InterceptorNames(cfg, nil, nil)
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageEnrich, server.StageLogging}, names)
+	suite.Len(server.BuildInterceptors(cfg, nil, nil), len(names))
+}
+
+func (suite *ChainTestSuite) TestInterceptorNames_RequestLimitAfterMetadataLimit() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192, MaxRequestBytes: map[string]int{"/authpb.AuthService/Login": 4096}, MultiTenant: true}
+
+	// Act
+	names := server.InterceptorNames(cfg, nil, nil)
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageMetadataLimit, server.StageRequestLimit, server.StageEnrich, server.StageLogging, server.StageTenant}, names)
+}
+
+func (suite *ChainTestSuite) TestInterceptorNames_MaintenanceIsFirstGuard() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192}
+
+	// Act
+	names := server.InterceptorNames(cfg, nil, server.NewMaintenance(false, ""))
+
+	// Assert
+	suite.Equal([]string{server.StageRecovery, server.StageVersion, server.StageMetadataLimit, server.StageEnrich, server.StageLogging, server.StageMaintenance}, names)
+}
+
+// ===== BEHAVIOR TESTS =====
+
+func (suite *ChainTestSuite) TestBuildInterceptors_OversizedMetadataIsRejectedBeforeLogging() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 16}
+	info := &grpc.UnaryServerInfo{FullMethod: "/authpb.AuthService/Login"}
+	oversized := strings.Repeat("a", 64)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.UserAgentMetadataKey, oversized))
+	handler := chainUnary(server.BuildInterceptors(cfg, nil, nil), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+
+	// Act
+	_, err := handler(ctx, nil)
+
+	// Assert
+	suite.Equal(codes.ResourceExhausted, status.Code(err))
+	suite.Nil(suite.logs.FindMessage("gRPC request completed"))
+	entry := suite.logs.FindMessage("Request metadata too large")
+	suite.Require().NotNil(entry)
+	suite.Equal("/authpb.AuthService/Login", entry["method"])
+	for _, entry := range suite.logs.Entries() {
+		for _, value := range entry {
+			suite.NotContains(fmt.Sprint(value), oversized)
+		}
+	}
+}
+
+func (suite *ChainTestSuite) TestBuildInterceptors_GuardRejectionIsLogged() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192}
+	info := &grpc.UnaryServerInfo{FullMethod: "/authpb.AuthService/Login"}
+	handler := chainUnary(server.BuildInterceptors(cfg, nil, server.NewMaintenance(true, "")), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+
+	// Act
+	_, err := handler(context.Background(), nil)
+
+	// Assert
+	suite.Equal(codes.Unavailable, status.Code(err))
+	entry := suite.logs.Last()
+	suite.Require().NotNil(entry)
+	suite.Equal("gRPC request completed", entry["msg"])
+	suite.Equal("Unavailable", entry["grpc_status"])
+}
+
+func (suite *ChainTestSuite) TestBuildInterceptors_RecoveryIsOutermost() {
+	// Arrange
+	cfg := &config.Config{MaxMetadataBytes: 8192}
+	info := &grpc.UnaryServerInfo{FullMethod: "/authpb.AuthService/Login"}
+	handler := chainUnary(server.BuildInterceptors(cfg, nil, nil), info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+
+	// Act
+	_, err := handler(context.Background(), nil)
+
+	// Assert
+	suite.Equal(codes.Internal, status.Code(err))
+	suite.NotNil(suite.logs.FindMessage("Recovered from panic"))
+}
+
+// authStream is a grpc.ServerStream stub carrying only a context
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func (suite *ChainTestSuite) TestBuildStreamInterceptors_UnauthenticatedStreamIsRejected() {
+	// Arrange
+	cfg := &config.Config{AuthPublicMethods: config.DefaultAuthPublicMethods}
+	interceptors := server.BuildStreamInterceptors(cfg, new(mocks.IAuthService), server.NewMaintenance(false, ""))
+	info := &grpc.StreamServerInfo{FullMethod: "/authpb.AuthService/StreamAuditEvents", IsServerStream: true}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(srv interface{}, ss grpc.ServerStream) error {
+			return interceptor(srv, ss, info, next)
+		}
+	}
+
+	// Act
+	err := handler(nil, &authStream{ctx: context.Background()})
+
+	// Assert
+	suite.Len(interceptors, 4)
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+// Run tests
+func TestChainTestSuite(t *testing.T) {
+	suite.Run(t, new(ChainTestSuite))
+}
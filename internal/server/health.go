@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ReadinessPath is where the readiness probe is served
+const ReadinessPath = "/readyz"
+
+// Pinger is a dependency whose reachability decides readiness
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// errStarting is reported by a StartupGate that has not been opened yet
+var errStarting = errors.New("startup has not finished")
+
+// healthServices are the names reported by the gRPC health service, the empty name is the whole server
+var healthServices = []string{"", authpb.AuthService_ServiceDesc.ServiceName}
+
+// NewHealthServer creates the standard gRPC health service reporting NOT_SERVING until a StartupGate opens it
+func NewHealthServer() *health.Server {
+	healthServer := health.NewServer()
+	for _, service := range healthServices {
+		healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return healthServer
+}
+
+// StartupGate is a Pinger that fails until Open succeeds and then delegates to the wrapped pinger
+// It keeps the readiness probe, and the gRPC health service when one is attached, from reporting ready
+// while startup steps that need the migrated schema, such as seeding the bootstrap admin, are still running
+type StartupGate struct {
+	pinger Pinger
+	open   atomic.Bool
+	health *health.Server
+}
+
+// NewStartupGate creates a closed gate in front of pinger
+func NewStartupGate(pinger Pinger) *StartupGate {
+	return &StartupGate{pinger: pinger}
+}
+
+// AttachHealth makes Open switch healthServer to SERVING, call it before Open
+func (g *StartupGate) AttachHealth(healthServer *health.Server) {
+	g.health = healthServer
+}
+
+// Open pings the wrapped pinger and opens the gate if it responds, a failed ping leaves the gate closed
+func (g *StartupGate) Open(ctx context.Context) error {
+	if g.pinger == nil {
+		return errors.New("no database configured")
+	}
+	if err := g.pinger.Ping(ctx); err != nil {
+		return err
+	}
+	g.open.Store(true)
+	if g.health != nil {
+		for _, service := range healthServices {
+			g.health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+		}
+	}
+	return nil
+}
+
+func (g *StartupGate) Ping(ctx context.Context) error {
+	if !g.open.Load() {
+		return errStarting
+	}
+	return g.pinger.Ping(ctx)
+}
+
+// ReadinessHandler answers 200 when pinger responds within timeout and 503 otherwise
+// The ping runs in its own goroutine so a pinger that ignores ctx cannot hang the probe
+func ReadinessHandler(pinger Pinger, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := pingWithTimeout(r.Context(), pinger, timeout); err != nil {
+			slog.WarnContext(r.Context(), "Readiness check failed", slog.String("error", err.Error()))
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+// pingWithTimeout pings under a deadline and gives up when it passes
+func pingWithTimeout(ctx context.Context, pinger Pinger, timeout time.Duration) error {
+	if pinger == nil {
+		return errors.New("no database configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- pinger.Ping(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,158 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakePinger returns err, or blocks until release is closed when block is set
+type fakePinger struct {
+	err     error
+	block   bool
+	release chan struct{}
+}
+
+func (p *fakePinger) Ping(ctx context.Context) error {
+	if p.block {
+		<-p.release
+	}
+	return p.err
+}
+
+// probe runs a readiness request against handler
+func probe(handler http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, server.ReadinessPath, nil))
+	return rec
+}
+
+func TestReadinessHandler_Ready(t *testing.T) {
+	// Arrange
+	handler := server.ReadinessHandler(&fakePinger{}, time.Second)
+
+	// Act
+	rec := probe(handler)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
+func TestReadinessHandler_PingError(t *testing.T) {
+	// Arrange
+	handler := server.ReadinessHandler(&fakePinger{err: errors.New("connection refused")}, time.Second)
+
+	// Act
+	rec := probe(handler)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestReadinessHandler_BlockingPingTimesOut(t *testing.T) {
+	// Arrange
+	pinger := &fakePinger{block: true, release: make(chan struct{})}
+	defer close(pinger.release)
+	timeout := 50 * time.Millisecond
+	handler := server.ReadinessHandler(pinger, timeout)
+
+	// Act
+	start := time.Now()
+	rec := probe(handler)
+	elapsed := time.Since(start)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestReadinessHandler_NilPinger(t *testing.T) {
+	// Arrange
+	handler := server.ReadinessHandler(nil, time.Second)
+
+	// Act
+	rec := probe(handler)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestStartupGate_NotReadyUntilOpened(t *testing.T) {
+	// Arrange
+	gate := server.NewStartupGate(&fakePinger{})
+	handler := server.ReadinessHandler(gate, time.Second)
+
+	// Act
+	before := probe(handler)
+	err := gate.Open(context.Background())
+	after := probe(handler)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, before.Code)
+	assert.Equal(t, http.StatusOK, after.Code)
+}
+
+func TestStartupGate_StaysClosedWhenPingFails(t *testing.T) {
+	// Arrange
+	gate := server.NewStartupGate(&fakePinger{err: errors.New("connection refused")})
+	handler := server.ReadinessHandler(gate, time.Second)
+
+	// Act
+	err := gate.Open(context.Background())
+	rec := probe(handler)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+// servingStatus asks healthServer for the status of service
+func servingStatus(t *testing.T, healthServer healthpb.HealthServer, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.GetStatus()
+}
+
+func TestStartupGate_HealthServingOnceOpened(t *testing.T) {
+	// Arrange
+	healthServer := server.NewHealthServer()
+	gate := server.NewStartupGate(&fakePinger{})
+	gate.AttachHealth(healthServer)
+	service := authpb.AuthService_ServiceDesc.ServiceName
+
+	// Act
+	before := servingStatus(t, healthServer, service)
+	err := gate.Open(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, before)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, service))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(t, healthServer, ""))
+}
+
+func TestStartupGate_HealthNotServingWhenPingFails(t *testing.T) {
+	// Arrange
+	healthServer := server.NewHealthServer()
+	gate := server.NewStartupGate(&fakePinger{err: errors.New("connection refused")})
+	gate.AttachHealth(healthServer)
+
+	// Act
+	err := gate.Open(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, healthServer, ""))
+}
@@ -0,0 +1,371 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxMetadataValueBytes caps a single metadata value before it reaches handlers and logs
+const MaxMetadataValueBytes = 1024
+
+// truncatedSuffix marks metadata values cut down to MaxMetadataValueBytes
+const truncatedSuffix = "...(truncated)"
+
+// MetadataLimitUnaryInterceptor rejects requests whose incoming metadata exceeds maxBytes
+// and truncates individual values longer than MaxMetadataValueBytes
+// It runs ahead of enrich and logging, so rejected metadata never reaches the log context
+func MetadataLimitUnaryInterceptor(maxBytes int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := limitMetadata(ctx, info.FullMethod, maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// MetadataLimitStreamInterceptor is the streaming variant of MetadataLimitUnaryInterceptor
+func MetadataLimitStreamInterceptor(maxBytes int) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := limitMetadata(ss.Context(), info.FullMethod, maxBytes)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// limitMetadata returns ctx with truncated metadata, or ResourceExhausted when it exceeds maxBytes
+// A rejection is logged with the method and size only, the metadata itself is never logged
+func limitMetadata(ctx context.Context, method string, maxBytes int) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	if size := metadataSize(md); size > maxBytes {
+		slog.WarnContext(ctx, "Request metadata too large",
+			slog.String("method", method), slog.Int("size", size), slog.Int("limit", maxBytes))
+		return nil, status.Errorf(codes.ResourceExhausted, "request metadata too large: %d bytes exceeds limit of %d", size, maxBytes)
+	}
+
+	return metadata.NewIncomingContext(ctx, truncateMetadata(md)), nil
+}
+
+// metadataSize returns the total size of metadata keys and values in bytes
+func metadataSize(md metadata.MD) int {
+	size := 0
+	for key, values := range md {
+		for _, value := range values {
+			size += len(key) + len(value)
+		}
+	}
+	return size
+}
+
+// truncateMetadata returns a copy of md with oversized values truncated
+func truncateMetadata(md metadata.MD) metadata.MD {
+	truncated := make(metadata.MD, len(md))
+	for key, values := range md {
+		copied := make([]string, len(values))
+		for i, value := range values {
+			if len(value) > MaxMetadataValueBytes {
+				value = value[:MaxMetadataValueBytes] + truncatedSuffix
+			}
+			copied[i] = value
+		}
+		truncated[key] = copied
+	}
+	return truncated
+}
+
+// MaxRequestSizeUnaryInterceptor rejects requests to a method in limits whose marshaled size exceeds its limit in bytes
+// Methods without a limit and non-protobuf requests pass through
+func MaxRequestSizeUnaryInterceptor(limits map[string]int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		limit, ok := limits[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		if err := checkRequestSize(req, limit); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// MaxRequestSizeStreamInterceptor is the streaming variant of MaxRequestSizeUnaryInterceptor
+// The limit applies to each message received on the stream
+func MaxRequestSizeStreamInterceptor(limits map[string]int) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		limit, ok := limits[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+		return handler(srv, &limitedServerStream{ServerStream: ss, limit: limit})
+	}
+}
+
+// checkRequestSize returns ResourceExhausted when the marshaled size of req exceeds limit
+func checkRequestSize(req interface{}, limit int) error {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if size := proto.Size(msg); size > limit {
+		return status.Errorf(codes.ResourceExhausted, "request too large: %d bytes exceeds limit of %d", size, limit)
+	}
+	return nil
+}
+
+// limitedServerStream rejects received messages larger than limit
+type limitedServerStream struct {
+	grpc.ServerStream
+	limit int
+}
+
+// RecvMsg receives the next message and checks its size
+func (s *limitedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkRequestSize(m, s.limit)
+}
+
+// TenantUnaryInterceptor stores the tenant id from incoming metadata in the request context
+// Requests without the tenant header keep the default schema, malformed ids are rejected as InvalidArgument
+// and ids outside allowedSchemas as PermissionDenied, so system schemas and unknown tenants are never queried
+func TenantUnaryInterceptor(allowedSchemas []string) grpc.UnaryServerInterceptor {
+	allowed := tenantAllowlist(allowedSchemas)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := tenantContext(ctx, allowed)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// TenantStreamInterceptor is the streaming variant of TenantUnaryInterceptor
+func TenantStreamInterceptor(allowedSchemas []string) grpc.StreamServerInterceptor {
+	allowed := tenantAllowlist(allowedSchemas)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := tenantContext(ss.Context(), allowed)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// tenantAllowlist indexes the configured tenant schemas
+func tenantAllowlist(schemas []string) map[string]bool {
+	allowed := make(map[string]bool, len(schemas))
+	for _, schema := range schemas {
+		allowed[schema] = true
+	}
+	return allowed
+}
+
+// tenantContext returns ctx carrying the tenant id from incoming metadata, if any
+func tenantContext(ctx context.Context, allowed map[string]bool) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(tenant.MetadataKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	if err := tenant.ValidateID(values[0]); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !allowed[values[0]] {
+		return nil, status.Errorf(codes.PermissionDenied, "unknown tenant %q", values[0])
+	}
+	return tenant.WithID(ctx, values[0]), nil
+}
+
+// UserAgentMetadataKey is the incoming metadata key carrying the client user agent
+const UserAgentMetadataKey = "user-agent"
+
+// EnrichUnaryInterceptor stores the caller's peer address and user agent in the logging context
+// so every log line of the request identifies where it came from, the address is masked on emission
+func EnrichUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			ctx = logging.WithClientIP(ctx, p.Addr.String())
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(UserAgentMetadataKey); len(values) > 0 {
+			ctx = logging.WithUserAgent(ctx, values[0])
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthorizationMetadataKey is the incoming metadata key carrying the caller's bearer token
+const AuthorizationMetadataKey = "authorization"
+
+// bearerScheme is the authorization scheme carrying a token, matched case-insensitively
+const bearerScheme = "Bearer"
+
+var (
+	// ErrMissingAuthorization is returned by BearerFromContext when no authorization metadata is present
+	ErrMissingAuthorization = errors.New("missing authorization metadata")
+	// ErrNotBearerScheme is returned by BearerFromContext when the authorization uses another scheme
+	ErrNotBearerScheme = errors.New("authorization scheme is not Bearer")
+	// ErrEmptyBearerToken is returned by BearerFromContext when the Bearer scheme carries no token
+	ErrEmptyBearerToken = errors.New("bearer token is empty")
+)
+
+// BearerFromContext returns the token of the "Bearer <token>" authorization value in the incoming metadata
+// The scheme is matched case-insensitively and surrounding whitespace is ignored
+func BearerFromContext(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationMetadataKey)
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return "", ErrMissingAuthorization
+	}
+
+	scheme, token, _ := strings.Cut(strings.TrimSpace(values[0]), " ")
+	if !strings.EqualFold(scheme, bearerScheme) {
+		return "", ErrNotBearerScheme
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrEmptyBearerToken
+	}
+	return token, nil
+}
+
+// AuthUnaryInterceptor validates the bearer token from incoming metadata and stamps
+// the caller's user id and email into the logging context for the rest of the request
+// Methods in publicMethods, full gRPC method names, are always served; a token sent to them only
+// stamps the logs when it validates and is ignored otherwise, whether empty, malformed, expired or revoked
+// Every other method requires a valid bearer token and is rejected as Unauthenticated without one
+func AuthUnaryInterceptor(authService services.IAuthService, publicMethods []string) grpc.UnaryServerInterceptor {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authService, public[info.FullMethod])
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the streaming variant of AuthUnaryInterceptor with the same public method handling
+func AuthStreamInterceptor(authService services.IAuthService, publicMethods []string) grpc.StreamServerInterceptor {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authService, public[info.FullMethod])
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate validates the bearer token in ctx and returns ctx stamped with the caller
+// A public method never fails, the token is only used when it validates
+func authenticate(ctx context.Context, authService services.IAuthService, public bool) (context.Context, error) {
+	token, err := BearerFromContext(ctx)
+	if err != nil {
+		if public {
+			return ctx, nil
+		}
+		if errors.Is(err, ErrMissingAuthorization) || errors.Is(err, ErrNotBearerScheme) {
+			return nil, status.Error(codes.Unauthenticated, "bearer token required")
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	claims, err := authService.ValidateToken(ctx, token)
+	if err != nil {
+		if public {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+
+	return logging.WithEmail(logging.WithUserID(ctx, claims.UserID), claims.Email), nil
+}
+
+// LoggingUnaryInterceptor records the request start and sampling decision and logs each completed RPC
+// with its duration and gRPC status code, as grpc_code (numeric) and grpc_status (name)
+// RPCs slower than slowThreshold are logged at WARN, a zero threshold disables this
+func LoggingUnaryInterceptor(slowThreshold time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = logging.WithSamplingDecision(logging.WithRequestStart(ctx))
+
+		resp, err := handler(ctx, req)
+
+		logCompletedRPC(ctx, info.FullMethod, slowThreshold, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor is the streaming counterpart of LoggingUnaryInterceptor
+// The duration covers the whole stream, from the first call until the handler returns
+func LoggingStreamInterceptor(slowThreshold time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := logging.WithSamplingDecision(logging.WithRequestStart(ss.Context()))
+
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+
+		logCompletedRPC(ctx, info.FullMethod, slowThreshold, err)
+		return err
+	}
+}
+
+// logCompletedRPC logs the outcome of an RPC started with logging.WithRequestStart
+func logCompletedRPC(ctx context.Context, method string, slowThreshold time.Duration, err error) {
+	code := status.Code(err)
+	attrs := []any{
+		slog.String("method", method),
+		slog.Int("grpc_code", int(code)),
+		slog.String("grpc_status", code.String()),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if start, ok := logging.RequestStart(ctx); ok && slowThreshold > 0 && time.Since(start) > slowThreshold {
+		attrs = append(attrs, slog.Float64("slow_threshold_ms", float64(slowThreshold.Microseconds())/1000))
+		logging.LogRequestDurationAt(ctx, slog.LevelWarn, "Slow gRPC request", attrs...)
+	} else {
+		logging.LogRequestDuration(ctx, "gRPC request completed", attrs...)
+	}
+}
+
+// contextServerStream overrides the context of a wrapped server stream
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden stream context
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,981 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/authpb"
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/Koshsky/subs-service/auth-service/internal/services/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/tenant"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type InterceptorsTestSuite struct {
+	suite.Suite
+	info          *grpc.UnaryServerInfo
+	logs          *logtest.CapturedLogs
+	defaultLogger *slog.Logger
+}
+
+func (suite *InterceptorsTestSuite) SetupTest() {
+	suite.info = &grpc.UnaryServerInfo{FullMethod: "/authpb.AuthService/Login"}
+	suite.defaultLogger = slog.Default()
+	var logger *slog.Logger
+	logger, suite.logs = logtest.NewTestLogger()
+	slog.SetDefault(logger)
+}
+
+func (suite *InterceptorsTestSuite) TearDownTest() {
+	slog.SetDefault(suite.defaultLogger)
+}
+
+// lastLogEntry returns the last captured log record
+func (suite *InterceptorsTestSuite) lastLogEntry() map[string]any {
+	entry := suite.logs.Last()
+	suite.Require().NotNil(entry)
+	return entry
+}
+
+// capturingHandler records the context passed to the handler
+func capturingHandler(captured *context.Context) grpc.UnaryHandler {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		*captured = ctx
+		return "ok", nil
+	}
+}
+
+// ===== METADATA LIMIT TESTS =====
+
+func (suite *InterceptorsTestSuite) TestMetadataLimit_UnderLimit() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-agent", "grpc-go"))
+	interceptor := server.MetadataLimitUnaryInterceptor(1024)
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+	md, _ := metadata.FromIncomingContext(handlerCtx)
+	suite.Equal([]string{"grpc-go"}, md.Get("user-agent"))
+}
+
+func (suite *InterceptorsTestSuite) TestMetadataLimit_OverLimit() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-padding", strings.Repeat("a", 2048)))
+	interceptor := server.MetadataLimitUnaryInterceptor(1024)
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(resp)
+	suite.Nil(handlerCtx)
+	suite.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+func (suite *InterceptorsTestSuite) TestMetadataLimit_TruncatesLongValues() {
+	// Arrange
+	long := strings.Repeat("a", server.MaxMetadataValueBytes+100)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-long", long))
+	interceptor := server.MetadataLimitUnaryInterceptor(8192)
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	md, _ := metadata.FromIncomingContext(handlerCtx)
+	value := md.Get("x-long")[0]
+	suite.True(strings.HasSuffix(value, "...(truncated)"))
+	suite.Less(len(value), len(long))
+}
+
+func (suite *InterceptorsTestSuite) TestMetadataLimit_NoMetadata() {
+	// Arrange
+	interceptor := server.MetadataLimitUnaryInterceptor(1)
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+}
+
+// ===== REQUEST SIZE LIMIT TESTS =====
+
+func (suite *InterceptorsTestSuite) TestMaxRequestSize_UnderLimit() {
+	// Arrange
+	interceptor := server.MaxRequestSizeUnaryInterceptor(map[string]int{suite.info.FullMethod: 256})
+	req := &authpb.LoginRequest{Email: "user@example.com", Password: "password123"}
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), req, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+}
+
+func (suite *InterceptorsTestSuite) TestMaxRequestSize_OverLimit() {
+	// Arrange
+	interceptor := server.MaxRequestSizeUnaryInterceptor(map[string]int{suite.info.FullMethod: 256})
+	req := &authpb.LoginRequest{Email: "user@example.com", Password: strings.Repeat("a", 512)}
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), req, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(resp)
+	suite.Nil(handlerCtx)
+	suite.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+func (suite *InterceptorsTestSuite) TestMaxRequestSize_UnlimitedMethod() {
+	// Arrange
+	interceptor := server.MaxRequestSizeUnaryInterceptor(map[string]int{"/authpb.AuthService/Register": 16})
+	req := &authpb.LoginRequest{Email: "user@example.com", Password: strings.Repeat("a", 512)}
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), req, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+}
+
+// ===== LOGGING TESTS =====
+
+func (suite *InterceptorsTestSuite) TestLogging_LogsMethodAndDuration() {
+	// Arrange
+	interceptor := server.LoggingUnaryInterceptor(time.Minute)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "ok", nil
+	}
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+	entry := suite.lastLogEntry()
+	suite.Equal("gRPC request completed", entry["msg"])
+	suite.Equal(suite.info.FullMethod, entry["method"])
+	suite.GreaterOrEqual(entry["duration_ms"], 10.0)
+	suite.NotContains(entry, "error")
+	suite.Equal(float64(codes.OK), entry["grpc_code"])
+	suite.Equal("OK", entry["grpc_status"])
+}
+
+func (suite *InterceptorsTestSuite) TestLogging_LogsStatusCode() {
+	// Arrange
+	interceptor := server.LoggingUnaryInterceptor(time.Minute)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unauthenticated, "token expired")
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().Error(err)
+	entry := suite.lastLogEntry()
+	suite.Equal(float64(codes.Unauthenticated), entry["grpc_code"])
+	suite.Equal("Unauthenticated", entry["grpc_status"])
+}
+
+func (suite *InterceptorsTestSuite) TestLogging_LogsHandlerError() {
+	// Arrange
+	interceptor := server.LoggingUnaryInterceptor(time.Minute)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("handler failed")
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().Error(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("handler failed", entry["error"])
+	suite.Contains(entry, "duration_ms")
+	suite.Equal("Unknown", entry["grpc_status"])
+}
+
+func (suite *InterceptorsTestSuite) TestLogging_SlowRequestLoggedAtWarn() {
+	// Arrange
+	threshold := 20 * time.Millisecond
+	interceptor := server.LoggingUnaryInterceptor(threshold)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		time.Sleep(30 * time.Millisecond)
+		return "ok", nil
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("WARN", entry["level"])
+	suite.Equal("Slow gRPC request", entry["msg"])
+	suite.Equal(suite.info.FullMethod, entry["method"])
+	suite.Greater(entry["duration_ms"], 20.0)
+	suite.Equal(20.0, entry["slow_threshold_ms"])
+}
+
+func (suite *InterceptorsTestSuite) TestLogging_FastRequestLoggedAtInfo() {
+	// Arrange
+	interceptor := server.LoggingUnaryInterceptor(time.Second)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("INFO", entry["level"])
+	suite.NotContains(entry, "slow_threshold_ms")
+}
+
+func (suite *InterceptorsTestSuite) TestLogging_ZeroThresholdDisablesSlowLog() {
+	// Arrange
+	interceptor := server.LoggingUnaryInterceptor(0)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "ok", nil
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("INFO", suite.lastLogEntry()["level"])
+}
+
+// ===== TENANT TESTS =====
+
+func (suite *InterceptorsTestSuite) TestTenant_StoresTenantID() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.MetadataKey, "acme"))
+	interceptor := server.TenantUnaryInterceptor([]string{"acme"})
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	id, ok := tenant.FromContext(handlerCtx)
+	suite.True(ok)
+	suite.Equal("acme", id)
+	suite.Equal("acme", tenant.Schema(handlerCtx))
+}
+
+func (suite *InterceptorsTestSuite) TestTenant_NoHeaderUsesDefaultSchema() {
+	// Arrange
+	interceptor := server.TenantUnaryInterceptor([]string{"acme"})
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(tenant.DefaultSchema, tenant.Schema(handlerCtx))
+}
+
+func (suite *InterceptorsTestSuite) TestTenant_RejectsMalformedID() {
+	// Arrange
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.MetadataKey, "acme.users"))
+	interceptor := server.TenantUnaryInterceptor([]string{"acme"})
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+	suite.Nil(handlerCtx)
+}
+
+func (suite *InterceptorsTestSuite) TestTenant_RejectsSchemaOutsideAllowlist() {
+	for _, id := range []string{"pg_catalog", "information_schema", "globex"} {
+		suite.Run(id, func() {
+			// Arrange
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.MetadataKey, id))
+			interceptor := server.TenantUnaryInterceptor([]string{"acme"})
+			var handlerCtx context.Context
+
+			// Act
+			_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+			// Assert
+			suite.Equal(codes.PermissionDenied, status.Code(err))
+			suite.Nil(handlerCtx)
+		})
+	}
+}
+
+// ===== ENRICH TESTS =====
+
+func (suite *InterceptorsTestSuite) TestEnrich_LogsPeerAddressAndUserAgent() {
+	// Arrange
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 54321}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(server.UserAgentMetadataKey, "grpc-go/1.75.0"))
+	interceptor := server.EnrichUnaryInterceptor()
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		slog.InfoContext(ctx, "Handling request")
+		return "ok", nil
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.lastLogEntry()
+	suite.Equal("203.0.113.***:54321", entry["client_ip"])
+	suite.Equal("grpc-go/1.75.0", entry["user_agent"])
+}
+
+func (suite *InterceptorsTestSuite) TestEnrich_NoPeerOrMetadata() {
+	// Arrange
+	interceptor := server.EnrichUnaryInterceptor()
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(logging.LogCtx{}, logging.FromContext(handlerCtx))
+}
+
+// ===== AUTH TESTS =====
+
+func (suite *InterceptorsTestSuite) TestAuth_StampsCallerIntoLogs() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	authService.On("ValidateToken", mock.Anything, "valid-token").
+		Return(&services.Claims{UserID: "user-1", Email: "john.doe@example.com"}, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer valid-token"))
+	interceptor := server.AuthUnaryInterceptor(authService, config.DefaultAuthPublicMethods)
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		slog.InfoContext(ctx, "Handling request")
+		return "ok", nil
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := suite.logs.FindMessage("Handling request")
+	suite.Require().NotNil(entry)
+	suite.Equal("user-1", entry["user_id"])
+	suite.Equal("j***@example.com", entry["email"])
+	authService.AssertExpectations(suite.T())
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_NoTokenPassesThrough() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	interceptor := server.AuthUnaryInterceptor(authService, config.DefaultAuthPublicMethods)
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(logging.FromContext(handlerCtx).UserID)
+	authService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything, mock.Anything)
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_RejectsInvalidToken() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	authService.On("ValidateToken", mock.Anything, "expired-token").Return(nil, errors.New("token is expired"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer expired-token"))
+	interceptor := server.AuthUnaryInterceptor(authService, nil)
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Nil(handlerCtx)
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_PublicMethodIgnoresUnusableToken() {
+	tests := []struct {
+		name          string
+		authorization string
+		validateErr   error
+	}{
+		{name: "Expired token", authorization: "Bearer expired-token", validateErr: errors.New("token is expired")},
+		{name: "Revoked token", authorization: "Bearer revoked-token", validateErr: errors.New("token has been revoked")},
+		{name: "Empty token", authorization: "Bearer "},
+		{name: "Malformed header", authorization: "Basic dXNlcjpwYXNz"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			authService := new(mocks.IAuthService)
+			if tt.validateErr != nil {
+				authService.On("ValidateToken", mock.Anything, mock.Anything).Return(nil, tt.validateErr)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, tt.authorization))
+			interceptor := server.AuthUnaryInterceptor(authService, config.DefaultAuthPublicMethods)
+			var handlerCtx context.Context
+
+			// Act
+			_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Require().NotNil(handlerCtx)
+			suite.Empty(logging.FromContext(handlerCtx).UserID)
+			authService.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_PublicMethods() {
+	tests := []struct {
+		name          string
+		publicMethods []string
+		expectedCode  codes.Code
+	}{
+		{name: "Protected method requires a token", publicMethods: []string{"/authpb.AuthService/Register"}, expectedCode: codes.Unauthenticated},
+		{name: "Method moved to public bypasses auth", publicMethods: []string{"/authpb.AuthService/Register", suite.info.FullMethod}, expectedCode: codes.OK},
+		{name: "No public methods", publicMethods: nil, expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			authService := new(mocks.IAuthService)
+			interceptor := server.AuthUnaryInterceptor(authService, tt.publicMethods)
+			var handlerCtx context.Context
+
+			// Act
+			_, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+			// Assert
+			suite.Equal(tt.expectedCode, status.Code(err))
+			suite.Equal(tt.expectedCode == codes.OK, handlerCtx != nil)
+			authService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_ProtectedMethodAcceptsValidToken() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	authService.On("ValidateToken", mock.Anything, "valid-token").
+		Return(&services.Claims{UserID: "user-1", Email: "john.doe@example.com"}, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer valid-token"))
+	interceptor := server.AuthUnaryInterceptor(authService, nil)
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("user-1", logging.FromContext(handlerCtx).UserID)
+	authService.AssertExpectations(suite.T())
+}
+
+func (suite *InterceptorsTestSuite) TestAuth_RejectsEmptyToken() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer "))
+	interceptor := server.AuthUnaryInterceptor(authService, nil)
+	var handlerCtx context.Context
+
+	// Act
+	_, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Nil(handlerCtx)
+	authService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything, mock.Anything)
+}
+
+func (suite *InterceptorsTestSuite) TestBearerFromContext() {
+	tests := []struct {
+		name          string
+		authorization []string
+		expectedToken string
+		expectedErr   error
+	}{
+		{name: "Well-formed header", authorization: []string{"Bearer abc.def.ghi"}, expectedToken: "abc.def.ghi"},
+		{name: "Lowercase scheme", authorization: []string{"bearer abc.def.ghi"}, expectedToken: "abc.def.ghi"},
+		{name: "Surrounding whitespace", authorization: []string{"  BEARER   abc.def.ghi  "}, expectedToken: "abc.def.ghi"},
+		{name: "Missing header", expectedErr: server.ErrMissingAuthorization},
+		{name: "Blank header", authorization: []string{"   "}, expectedErr: server.ErrMissingAuthorization},
+		{name: "Wrong scheme", authorization: []string{"Basic dXNlcjpwYXNz"}, expectedErr: server.ErrNotBearerScheme},
+		{name: "Scheme without separator", authorization: []string{"Bearerabc"}, expectedErr: server.ErrNotBearerScheme},
+		{name: "Empty token", authorization: []string{"Bearer "}, expectedErr: server.ErrEmptyBearerToken},
+		{name: "Scheme only", authorization: []string{"Bearer"}, expectedErr: server.ErrEmptyBearerToken},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			md := metadata.MD{}
+			if tt.authorization != nil {
+				md.Set(server.AuthorizationMetadataKey, tt.authorization...)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			// Act
+			token, err := server.BearerFromContext(ctx)
+
+			// Assert
+			suite.Equal(tt.expectedToken, token)
+			if tt.expectedErr != nil {
+				suite.ErrorIs(err, tt.expectedErr)
+			} else {
+				suite.NoError(err)
+			}
+		})
+	}
+}
+
+func (suite *InterceptorsTestSuite) TestBearerFromContext_NoMetadata() {
+	// Act
+	token, err := server.BearerFromContext(context.Background())
+
+	// Assert
+	suite.Empty(token)
+	suite.ErrorIs(err, server.ErrMissingAuthorization)
+}
+
+// ===== RECOVERY TESTS =====
+
+func (suite *InterceptorsTestSuite) TestRecovery_LogsPanicDetails() {
+	// Arrange
+	interceptor := server.RecoveryUnaryInterceptor()
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil map write")
+	}
+
+	// Act
+	resp, err := interceptor(ctx, nil, suite.info, handler)
+
+	// Assert
+	suite.Nil(resp)
+	suite.Equal(codes.Internal, status.Code(err))
+	entry := suite.lastLogEntry()
+	suite.Equal("ERROR", entry["level"])
+	suite.Equal(suite.info.FullMethod, entry["method"])
+	suite.Equal("nil map write", entry["panic"])
+	suite.Equal("req-42", entry["request_id"])
+	stack := entry["stack"].(string)
+	suite.Contains(stack, "interceptors_test.go")
+	suite.NotContains(stack, "runtime/debug.Stack")
+	suite.NotContains(entry["msg"], "nil map write")
+}
+
+func (suite *InterceptorsTestSuite) TestRecovery_PassesThroughWithoutPanic() {
+	// Arrange
+	interceptor := server.RecoveryUnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+	suite.Empty(suite.logs.Entries())
+}
+
+// ===== MAINTENANCE TESTS =====
+
+func (suite *InterceptorsTestSuite) TestMaintenance_OnRejectsMutatingRPC() {
+	// Arrange
+	interceptor := server.MaintenanceUnaryInterceptor(server.NewMaintenance(true, "back at 10:00"))
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Nil(resp)
+	suite.Nil(handlerCtx)
+	suite.Equal(codes.Unavailable, status.Code(err))
+	suite.Equal("back at 10:00", status.Convert(err).Message())
+}
+
+func (suite *InterceptorsTestSuite) TestMaintenance_OffServesRPC() {
+	// Arrange
+	interceptor := server.MaintenanceUnaryInterceptor(server.NewMaintenance(false, ""))
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+}
+
+func (suite *InterceptorsTestSuite) TestMaintenance_HealthAndReadOnlyRPCsExempt() {
+	// Arrange
+	interceptor := server.MaintenanceUnaryInterceptor(server.NewMaintenance(true, ""))
+
+	for _, method := range []string{"/grpc.health.v1.Health/Check", "/authpb.AuthService/ValidateToken"} {
+		var handlerCtx context.Context
+
+		// Act
+		resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, capturingHandler(&handlerCtx))
+
+		// Assert
+		suite.Require().NoError(err, method)
+		suite.Equal("ok", resp, method)
+	}
+}
+
+func (suite *InterceptorsTestSuite) TestMaintenance_ToggleAtRuntime() {
+	// Arrange
+	maintenance := server.NewMaintenance(false, "")
+	interceptor := server.MaintenanceUnaryInterceptor(maintenance)
+	var handlerCtx context.Context
+
+	// Act
+	enabled := maintenance.Toggle()
+	_, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.True(enabled)
+	suite.Equal(codes.Unavailable, status.Code(err))
+	suite.Equal(server.DefaultMaintenanceMessage, status.Convert(err).Message())
+}
+
+// ===== VERSION TESTS =====
+
+// fakeTransportStream is a grpc.ServerTransportStream stub recording the response header
+type fakeTransportStream struct {
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) Method() string { return "/authpb.AuthService/Login" }
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeTransportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *fakeTransportStream) SetTrailer(metadata.MD) error { return nil }
+
+func (suite *InterceptorsTestSuite) TestVersion_SetsResponseHeader() {
+	// Arrange
+	stream := &fakeTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	interceptor := server.VersionUnaryInterceptor("v1.2.3")
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(ctx, nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+	suite.Equal([]string{"v1.2.3"}, stream.header.Get(server.VersionMetadataKey))
+}
+
+func (suite *InterceptorsTestSuite) TestVersion_NoTransportStream() {
+	// Arrange
+	interceptor := server.VersionUnaryInterceptor("v1.2.3")
+	var handlerCtx context.Context
+
+	// Act
+	resp, err := interceptor(context.Background(), nil, suite.info, capturingHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("ok", resp)
+}
+
+func (suite *InterceptorsTestSuite) TestBuildVersion_NotEmpty() {
+	suite.NotEmpty(server.BuildVersion())
+}
+
+// ===== STREAM TESTS =====
+
+// fakeServerStream is a grpc.ServerStream stub carrying only a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (suite *InterceptorsTestSuite) streamInfo() *grpc.StreamServerInfo {
+	return &grpc.StreamServerInfo{FullMethod: "/authpb.AuthService/StreamAuditEvents", IsServerStream: true}
+}
+
+func (suite *InterceptorsTestSuite) TestLoggingStream_LogsMethodAndDuration() {
+	// Arrange
+	interceptor := server.LoggingStreamInterceptor(time.Minute)
+	stream := &fakeServerStream{ctx: logging.WithRequestID(context.Background(), "req-7")}
+	var handlerCtx context.Context
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		handlerCtx = ss.Context()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	_, ok := logging.RequestStart(handlerCtx)
+	suite.True(ok)
+	entry := suite.lastLogEntry()
+	suite.Equal("gRPC request completed", entry["msg"])
+	suite.Equal("/authpb.AuthService/StreamAuditEvents", entry["method"])
+	suite.Equal("OK", entry["grpc_status"])
+	suite.Equal("req-7", entry["request_id"])
+	suite.GreaterOrEqual(entry["duration_ms"], 10.0)
+}
+
+func (suite *InterceptorsTestSuite) TestLoggingStream_LogsStatusCode() {
+	// Arrange
+	interceptor := server.LoggingStreamInterceptor(time.Minute)
+	stream := &fakeServerStream{ctx: context.Background()}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return status.Error(codes.PermissionDenied, "not allowed")
+	}
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), handler)
+
+	// Assert
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+	entry := suite.lastLogEntry()
+	suite.Equal(float64(codes.PermissionDenied), entry["grpc_code"])
+	suite.Equal("PermissionDenied", entry["grpc_status"])
+	suite.Contains(entry["error"], "not allowed")
+}
+
+func (suite *InterceptorsTestSuite) TestRecoveryStream_LogsPanicDetails() {
+	// Arrange
+	interceptor := server.RecoveryStreamInterceptor()
+	stream := &fakeServerStream{ctx: logging.WithRequestID(context.Background(), "req-43")}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		panic("stream closed twice")
+	}
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), handler)
+
+	// Assert
+	suite.Equal(codes.Internal, status.Code(err))
+	entry := suite.lastLogEntry()
+	suite.Equal("ERROR", entry["level"])
+	suite.Equal("/authpb.AuthService/StreamAuditEvents", entry["method"])
+	suite.Equal("stream closed twice", entry["panic"])
+	suite.Equal("req-43", entry["request_id"])
+	suite.Contains(entry["stack"], "interceptors_test.go")
+}
+
+func (suite *InterceptorsTestSuite) TestRecoveryStream_PassesThroughWithoutPanic() {
+	// Arrange
+	interceptor := server.RecoveryStreamInterceptor()
+	stream := &fakeServerStream{ctx: context.Background()}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	}
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), handler)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Empty(suite.logs.Entries())
+}
+
+// capturingStreamHandler records the stream context the handler was called with
+func capturingStreamHandler(captured *context.Context) grpc.StreamHandler {
+	return func(srv interface{}, ss grpc.ServerStream) error {
+		*captured = ss.Context()
+		return nil
+	}
+}
+
+func (suite *InterceptorsTestSuite) TestMaintenanceStream_OnRejectsStream() {
+	// Arrange
+	interceptor := server.MaintenanceStreamInterceptor(server.NewMaintenance(true, "back at 10:00"))
+	stream := &fakeServerStream{ctx: context.Background()}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Nil(handlerCtx)
+	suite.Equal(codes.Unavailable, status.Code(err))
+	suite.Equal("back at 10:00", status.Convert(err).Message())
+}
+
+func (suite *InterceptorsTestSuite) TestTenantStream_StoresTenantID() {
+	// Arrange
+	interceptor := server.TenantStreamInterceptor([]string{"acme"})
+	stream := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenant.MetadataKey, "acme"))}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("acme", tenant.Schema(handlerCtx))
+}
+
+func (suite *InterceptorsTestSuite) TestMetadataLimitStream_OverLimit() {
+	// Arrange
+	interceptor := server.MetadataLimitStreamInterceptor(16)
+	stream := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-large", strings.Repeat("a", 64)))}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Nil(handlerCtx)
+	suite.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+// recvServerStream is a grpc.ServerStream stub that receives a fixed message
+type recvServerStream struct {
+	fakeServerStream
+	msg proto.Message
+}
+
+func (s *recvServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.msg)
+	return nil
+}
+
+func (suite *InterceptorsTestSuite) TestMaxRequestSizeStream_RejectsOversizedMessage() {
+	// Arrange
+	interceptor := server.MaxRequestSizeStreamInterceptor(map[string]int{suite.streamInfo().FullMethod: 256})
+	stream := &recvServerStream{
+		fakeServerStream: fakeServerStream{ctx: context.Background()},
+		msg:              &authpb.LoginRequest{Email: "user@example.com", Password: strings.Repeat("a", 512)},
+	}
+	var recvErr error
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		recvErr = ss.RecvMsg(&authpb.LoginRequest{})
+		return recvErr
+	}
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), handler)
+
+	// Assert
+	suite.Equal(codes.ResourceExhausted, status.Code(err))
+	suite.Equal(codes.ResourceExhausted, status.Code(recvErr))
+}
+
+func (suite *InterceptorsTestSuite) TestAuthStream_ProtectedStreamRequiresToken() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	interceptor := server.AuthStreamInterceptor(authService, config.DefaultAuthPublicMethods)
+	stream := &fakeServerStream{ctx: context.Background()}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+	suite.Nil(handlerCtx)
+}
+
+func (suite *InterceptorsTestSuite) TestAuthStream_StampsCaller() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	authService.On("ValidateToken", mock.Anything, "valid-token").
+		Return(&services.Claims{UserID: "user-1", Email: "john.doe@example.com"}, nil)
+	interceptor := server.AuthStreamInterceptor(authService, config.DefaultAuthPublicMethods)
+	stream := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer valid-token"))}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("user-1", logging.FromContext(handlerCtx).UserID)
+	authService.AssertExpectations(suite.T())
+}
+
+func (suite *InterceptorsTestSuite) TestAuthStream_PublicMethodIgnoresExpiredToken() {
+	// Arrange
+	authService := new(mocks.IAuthService)
+	authService.On("ValidateToken", mock.Anything, "expired-token").Return(nil, errors.New("token is expired"))
+	interceptor := server.AuthStreamInterceptor(authService, []string{suite.streamInfo().FullMethod})
+	stream := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.AuthorizationMetadataKey, "Bearer expired-token"))}
+	var handlerCtx context.Context
+
+	// Act
+	err := interceptor(nil, stream, suite.streamInfo(), capturingStreamHandler(&handlerCtx))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NotNil(handlerCtx)
+	suite.Empty(logging.FromContext(handlerCtx).UserID)
+}
+
+func TestInterceptorsTestSuite(t *testing.T) {
+	suite.Run(t, new(InterceptorsTestSuite))
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaintenanceMessage is returned to rejected callers when no message is configured
+const DefaultMaintenanceMessage = "service is under maintenance, please retry later"
+
+// maintenanceExemptMethods keep being served in maintenance mode: health checks and read-only RPCs
+var maintenanceExemptMethods = map[string]bool{
+	"/grpc.health.v1.Health/Check":      true,
+	"/grpc.health.v1.Health/Watch":      true,
+	"/authpb.AuthService/ValidateToken": true,
+}
+
+// Maintenance is the runtime maintenance mode switch, safe for concurrent use
+type Maintenance struct {
+	enabled atomic.Bool
+	message string
+}
+
+// NewMaintenance creates a switch in the given state, an empty message uses DefaultMaintenanceMessage
+func NewMaintenance(enabled bool, message string) *Maintenance {
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+	m := &Maintenance{message: message}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *Maintenance) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set turns maintenance mode on or off
+func (m *Maintenance) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Toggle flips maintenance mode and returns the new state
+func (m *Maintenance) Toggle() bool {
+	for {
+		current := m.enabled.Load()
+		if m.enabled.CompareAndSwap(current, !current) {
+			return !current
+		}
+	}
+}
+
+// MaintenanceUnaryInterceptor rejects non-exempt RPCs with Unavailable while maintenance mode is on
+func MaintenanceUnaryInterceptor(m *Maintenance) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if m.Enabled() && !maintenanceExemptMethods[info.FullMethod] {
+			return nil, status.Error(codes.Unavailable, m.message)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// MaintenanceStreamInterceptor is the streaming variant of MaintenanceUnaryInterceptor
+func MaintenanceStreamInterceptor(m *Maintenance) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if m.Enabled() && !maintenanceExemptMethods[info.FullMethod] {
+			return status.Error(codes.Unavailable, m.message)
+		}
+		return handler(srv, ss)
+	}
+}
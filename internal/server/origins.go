@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AllowedOriginsMiddleware rejects browser requests whose Origin header is not allowed
+// Requests without an Origin header and requests to exempt paths (probes) pass through
+// An entry of "*" allows any origin, "https://*.example.com" allows any subdomain
+func AllowedOriginsMiddleware(allowed []string, exemptPaths []string) func(http.Handler) http.Handler {
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if _, ok := exempt[r.URL.Path]; ok || origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if !originAllowed(origin, allowed) {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// originAllowed reports whether origin matches any allowed entry
+func originAllowed(origin string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if ok && strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+	return false
+}
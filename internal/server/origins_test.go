@@ -0,0 +1,112 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedOriginsMiddleware(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		allowed        []string
+		path           string
+		method         string
+		origin         string
+		expectedStatus int
+	}{
+		{
+			name:           "Allowed origin",
+			allowed:        []string{"https://admin.example.com"},
+			path:           "/metrics",
+			origin:         "https://admin.example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Disallowed origin",
+			allowed:        []string{"https://admin.example.com"},
+			path:           "/metrics",
+			origin:         "https://evil.example.org",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Wildcard allows any origin",
+			allowed:        []string{"*"},
+			path:           "/metrics",
+			origin:         "https://anything.example.org",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Subdomain wildcard",
+			allowed:        []string{"https://*.example.com"},
+			path:           "/metrics",
+			origin:         "https://grafana.example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Subdomain wildcard rejects other scheme",
+			allowed:        []string{"https://*.example.com"},
+			path:           "/metrics",
+			origin:         "http://grafana.example.com",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Empty allowlist rejects browser requests",
+			allowed:        nil,
+			path:           "/metrics",
+			origin:         "https://admin.example.com",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Request without origin passes",
+			allowed:        nil,
+			path:           "/metrics",
+			origin:         "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Exempt probe path",
+			allowed:        nil,
+			path:           "/healthz",
+			origin:         "https://evil.example.org",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Preflight for allowed origin",
+			allowed:        []string{"https://admin.example.com"},
+			path:           "/metrics",
+			method:         http.MethodOptions,
+			origin:         "https://admin.example.com",
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler := server.AllowedOriginsMiddleware(tt.allowed, []string{"/healthz"})(okHandler)
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusForbidden && tt.origin != "" && tt.path != "/healthz" {
+				assert.Equal(t, tt.origin, rec.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPanicStackFrames caps how many stack frames a recovered panic logs
+const maxPanicStackFrames = 16
+
+// RecoveryUnaryInterceptor turns handler panics into Internal errors
+// The panic value, gRPC method and a trimmed stack are logged as structured fields
+// on the context logger, so request fields such as request_id are attached too
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				resp, err = nil, panicError(ctx, info.FullMethod, recovered)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor is the streaming counterpart of RecoveryUnaryInterceptor
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = panicError(ss.Context(), info.FullMethod, recovered)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// panicError logs a recovered panic and returns the Internal error sent to the client
+func panicError(ctx context.Context, method string, recovered any) error {
+	slog.ErrorContext(ctx, "Recovered from panic in gRPC handler",
+		slog.String("method", method),
+		slog.String("panic", fmt.Sprint(recovered)),
+		slog.String("stack", trimStack(debug.Stack(), maxPanicStackFrames)),
+	)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// trimStack keeps the frames above the panic site, dropping the goroutine header
+// and runtime/debug and recovery frames, limited to maxFrames
+func trimStack(stack []byte, maxFrames int) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+
+	// After the goroutine header every frame is a function line followed by a file line
+	start := 1
+	for i := 1; i+1 < len(lines); i += 2 {
+		if strings.HasPrefix(lines[i], "panic(") {
+			start = i + 2
+			break
+		}
+	}
+	if start >= len(lines) {
+		start = 1
+	}
+
+	frames := lines[start:]
+	if len(frames) > maxFrames*2 {
+		frames = frames[:maxFrames*2]
+	}
+	for i := range frames {
+		frames[i] = strings.TrimSpace(frames[i])
+	}
+	return strings.Join(frames, "\n")
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+)
+
+// DefaultCipherSuites are the TLS 1.2 suites allowed when none are configured:
+// ECDHE key exchange with AEAD ciphers only
+var DefaultCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// BuildTLSConfig creates the server TLS config from the configured certificate,
+// minimum version and cipher suites
+// Cipher suites only apply to TLS 1.2, TLS 1.3 suites are fixed by the Go runtime
+func BuildTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(cfg.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites := DefaultCipherSuites
+	if len(cfg.TLSCipherSuites) > 0 {
+		if cipherSuites, err = parseCipherSuites(cfg.TLSCipherSuites); err != nil {
+			return nil, err
+		}
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+	}, nil
+}
+
+// parseTLSVersion converts "1.2" or "1.3" to a tls version, empty means TLS 1.2
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS minimum version %q, expected 1.2 or 1.3", version)
+	}
+}
+
+// parseCipherSuites resolves suite names such as TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+// Only suites Go considers secure are accepted
+func parseCipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure TLS cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,130 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a throwaway certificate and key and returns their paths
+func writeSelfSignedCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server-cert.pem")
+	keyFile := filepath.Join(dir, "server-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+
+	tests := []struct {
+		name               string
+		minVersion         string
+		cipherSuites       []string
+		expectedMinVersion uint16
+		expectedSuites     []uint16
+	}{
+		{
+			name:               "Defaults",
+			expectedMinVersion: tls.VersionTLS12,
+			expectedSuites:     server.DefaultCipherSuites,
+		},
+		{
+			name:               "TLS 1.3 minimum",
+			minVersion:         "1.3",
+			expectedMinVersion: tls.VersionTLS13,
+			expectedSuites:     server.DefaultCipherSuites,
+		},
+		{
+			name:               "Configured suites",
+			minVersion:         "1.2",
+			cipherSuites:       []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedSuites:     []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				TLSCertFile:     certFile,
+				TLSKeyFile:      keyFile,
+				TLSMinVersion:   tt.minVersion,
+				TLSCipherSuites: tt.cipherSuites,
+			}
+
+			tlsConfig, err := server.BuildTLSConfig(cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMinVersion, tlsConfig.MinVersion)
+			assert.Equal(t, tt.expectedSuites, tlsConfig.CipherSuites)
+			assert.Len(t, tlsConfig.Certificates, 1)
+		})
+	}
+}
+
+func TestBuildTLSConfig_Errors(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		errText string
+	}{
+		{
+			name:    "Unsupported version",
+			cfg:     &config.Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSMinVersion: "1.1"},
+			errText: "unsupported TLS minimum version",
+		},
+		{
+			name:    "Insecure suite",
+			cfg:     &config.Config{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			errText: "unsupported or insecure TLS cipher suite TLS_RSA_WITH_RC4_128_SHA",
+		},
+		{
+			name:    "Missing certificate",
+			cfg:     &config.Config{TLSCertFile: "nonexistent.crt", TLSKeyFile: "nonexistent.key"},
+			errText: "open nonexistent.crt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := server.BuildTLSConfig(tt.cfg)
+
+			assert.Nil(t, tlsConfig)
+			assert.ErrorContains(t, err, tt.errText)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// VersionMetadataKey is the response header carrying the server version
+const VersionMetadataKey = "x-auth-service-version"
+
+// unknownVersion is reported when the binary carries no usable build info
+const unknownVersion = "unknown"
+
+// BuildVersion returns the module version of the running binary, or the VCS revision for development builds
+func BuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return unknownVersion
+	}
+	if version := info.Main.Version; version != "" && version != "(devel)" {
+		return version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			return setting.Value
+		}
+	}
+	return unknownVersion
+}
+
+// VersionUnaryInterceptor sets the VersionMetadataKey response header to version on every RPC
+// The header is set before the handler runs so it accompanies error responses too
+func VersionUnaryInterceptor(version string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(VersionMetadataKey, version)); err != nil {
+			slog.DebugContext(ctx, "Cannot set version header", slog.String("error", err.Error()))
+		}
+		return handler(ctx, req)
+	}
+}
@@ -4,129 +4,701 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/features"
 	"github.com/Koshsky/subs-service/auth-service/internal/messaging"
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	"github.com/Koshsky/subs-service/auth-service/internal/repositories"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrInvalidCredentials is returned when an email/user id and password do not match
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrAccountSuspended is returned by Login when the credentials match a suspended account
+var ErrAccountSuspended = errors.New("account suspended")
+
+// ErrInvalidArgument is wrapped by errors caused by malformed input rather than by state
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// TokenTypeAccess labels access tokens in issuance logs and metrics
+const TokenTypeAccess = "access"
+
+const (
+	// DefaultMaxEmailLength is the RFC 5321 limit for an address, in bytes
+	DefaultMaxEmailLength = 254
+	// DefaultMaxPasswordLength bounds the input hashed per request, in bytes
+	DefaultMaxPasswordLength = 1024
+)
+
+// dummyPasswordHash is compared against when a user is missing so that
+// unknown users take as long to reject as wrong passwords
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
 // AuthService implements authentication business logic
 type AuthService struct {
-	userRepo      repositories.IUserRepository
-	messageBroker messaging.IMessageBroker
-	JWTSecret     []byte
+	userRepo          repositories.IUserRepository
+	messageBroker     messaging.IMessageBroker
+	JWTSecret         []byte
+	JWTKeyID          string
+	verificationKeys  map[string][]byte
+	passwordHasher    PasswordHasher
+	rehashOnLogin     bool
+	maxEmailLength    int
+	maxPasswordLength int
+	defaultLocale     string
+	defaultTimezone   string
+	// allowedEmailDomains restricts registration to these lowercase domains, nil allows all
+	allowedEmailDomains map[string]bool
+	// features toggles experimental behaviors, the zero value keeps every flag at its default
+	features features.Flags
+	// now is the clock used for token expiry, login and export timestamps and registration step timings, see SetClock
+	now func() time.Time
+	// revocations caches session revocation times looked up while validating tokens
+	revocations revocationCache
 }
 
 // NewAuthService creates a new AuthService instance
 func NewAuthService(userRepo repositories.IUserRepository, messageBroker messaging.IMessageBroker, cfg *config.Config) *AuthService {
-	if cfg == nil || cfg.JWTSecret == "" {
+	var signingKey []byte
+	if cfg != nil {
+		signingKey = cfg.JWTSigningKey()
+	}
+	if signingKey == nil {
 		return &AuthService{
-			userRepo:      userRepo,
-			messageBroker: messageBroker,
-			JWTSecret:     nil,
+			userRepo:          userRepo,
+			messageBroker:     messageBroker,
+			JWTSecret:         nil,
+			passwordHasher:    BcryptHasher{Cost: bcrypt.DefaultCost},
+			maxEmailLength:    DefaultMaxEmailLength,
+			maxPasswordLength: DefaultMaxPasswordLength,
+			now:               time.Now,
 		}
 	}
+
+	passwordHasher, err := NewPasswordHasher(cfg.PasswordHashAlgo, cfg.BcryptCost)
+	if err != nil {
+		slog.Warn("Falling back to bcrypt password hashing", slog.String("error", err.Error()))
+		passwordHasher = BcryptHasher{Cost: bcrypt.DefaultCost}
+	}
+
+	verificationKeys := make(map[string][]byte, len(cfg.JWTVerificationKeys))
+	for kid, secret := range cfg.JWTVerificationKeys {
+		verificationKeys[kid] = []byte(secret)
+	}
+
 	return &AuthService{
-		userRepo:      userRepo,
-		messageBroker: messageBroker,
-		JWTSecret:     []byte(cfg.JWTSecret),
+		userRepo:          userRepo,
+		messageBroker:     messageBroker,
+		JWTSecret:         signingKey,
+		JWTKeyID:          cfg.JWTKeyID,
+		verificationKeys:  verificationKeys,
+		passwordHasher:    passwordHasher,
+		rehashOnLogin:     cfg.PasswordRehash,
+		maxEmailLength:    positiveOr(cfg.MaxEmailLength, DefaultMaxEmailLength),
+		maxPasswordLength: positiveOr(cfg.MaxPasswordLength, DefaultMaxPasswordLength),
+		defaultLocale:     cfg.DefaultLocale,
+		defaultTimezone:   cfg.DefaultTimezone,
+
+		allowedEmailDomains: emailDomainSet(cfg.AllowedEmailDomains),
+		features:            cfg.Features,
+		now:                 time.Now,
+	}
+}
+
+// SetClock replaces the clock used for token expiry, login timestamps and registration step timings, nil restores time.Now
+func (s *AuthService) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	s.now = now
+}
+
+// emailDomainSet normalizes configured domains to a lookup set, nil when no domain is configured
+func emailDomainSet(domains []string) map[string]bool {
+	if len(domains) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		set[strings.ToLower(strings.TrimPrefix(domain, "@"))] = true
+	}
+	return set
+}
+
+// positiveOr returns value, or fallback when value is not positive
+func positiveOr(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// validateCredentialLengths rejects oversized input before any lookup or hashing
+func (s *AuthService) validateCredentialLengths(email, password string) error {
+	if len(email) > s.maxEmailLength {
+		return fmt.Errorf("%w: email must be at most %d bytes", ErrInvalidArgument, s.maxEmailLength)
+	}
+	if len(password) > s.maxPasswordLength {
+		return fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidArgument, s.maxPasswordLength)
+	}
+	return nil
+}
+
+// validateEmailDomain rejects emails outside the allowed domains when a restriction is configured
+func (s *AuthService) validateEmailDomain(email string) error {
+	if s.allowedEmailDomains == nil {
+		return nil
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 || !s.allowedEmailDomains[strings.ToLower(email[at+1:])] {
+		return fmt.Errorf("%w: email domain is not allowed", ErrInvalidArgument)
 	}
+	return nil
 }
 
 // Register registers a new user
 func (s *AuthService) Register(ctx context.Context, email, password string) (*models.User, error) {
-	_ = ctx // TODO: use ctx in future
+	return s.RegisterWithProfile(ctx, email, password, nil)
+}
+
+// RegisterWithProfile registers a new user together with optional profile fields
+// A nil profile behaves exactly like Register
+// The "User registered" log line carries the duration of each registration step, see registrationTimings
+func (s *AuthService) RegisterWithProfile(ctx context.Context, email, password string, profile *models.Profile) (*models.User, error) {
 	if s.userRepo == nil {
 		return nil, errors.New("user repository is not initialized")
 	}
 
+	var timings registrationTimings
+	start := s.now()
+
+	if err := s.validateCredentialLengths(email, password); err != nil {
+		return nil, err
+	}
+	if err := s.validateEmailDomain(email); err != nil {
+		return nil, err
+	}
+
+	if profile != nil {
+		if err := validateProfile(profile); err != nil {
+			return nil, fmt.Errorf("invalid profile: %w", err)
+		}
+	}
+
 	// Check if user already exists
-	exists, err := s.userRepo.UserExists(email)
+	exists, err := s.userRepo.UserExists(ctx, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check user existence: %w", err)
 	}
 	if exists {
 		return nil, errors.New("user already exists")
 	}
+	timings.validate = s.now().Sub(start)
+
+	user, err := s.createUser(ctx, email, password, profile, models.RoleUser, &timings)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "User registered", append([]any{slog.String("user_id", user.ID.String())}, timings.attrs()...)...)
+	return user, nil
+}
+
+// registrationTimings holds how long each registration step took, measured with the service clock
+type registrationTimings struct {
+	validate time.Duration
+	hash     time.Duration
+	insert   time.Duration
+	publish  time.Duration
+}
+
+// attrs returns the step durations as validate_ms, hash_ms, insert_ms and publish_ms log attributes
+func (t registrationTimings) attrs() []any {
+	return []any{
+		slog.Float64("validate_ms", durationMs(t.validate)),
+		slog.Float64("hash_ms", durationMs(t.hash)),
+		slog.Float64("insert_ms", durationMs(t.insert)),
+		slog.Float64("publish_ms", durationMs(t.publish)),
+	}
+}
+
+// durationMs converts d to fractional milliseconds, clamping the negative values a stepped-back clock can produce
+func durationMs(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+	return float64(d.Microseconds()) / 1000
+}
+
+// BootstrapAdmin creates an admin user when no users exist yet
+// It reports whether the admin was created, an existing user base is left untouched
+func (s *AuthService) BootstrapAdmin(ctx context.Context, email, password string) (bool, error) {
+	if s.userRepo == nil {
+		return false, errors.New("user repository is not initialized")
+	}
+
+	if err := s.validateCredentialLengths(email, password); err != nil {
+		return false, err
+	}
+
+	hasUsers, err := s.userRepo.HasUsers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing users: %w", err)
+	}
+	if hasUsers {
+		return false, nil
+	}
 
+	if _, err := s.createUser(ctx, email, password, nil, models.RoleAdmin, &registrationTimings{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createUser hashes password, stores the user and publishes the user created event
+// The duration of the hash, insert and publish steps is recorded in timings
+func (s *AuthService) createUser(ctx context.Context, email, password string, profile *models.Profile, role string, timings *registrationTimings) (*models.User, error) {
 	// Hash password in service layer
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	start := s.now()
+	hashedPassword, err := s.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
+	timings.hash = s.now().Sub(start)
 
 	// Create new user with hashed password
 	user := &models.User{
 		Email:    email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
+		Role:     role,
+	}
+	if profile != nil {
+		user.Profile = *profile
 	}
+	s.applyProfileDefaults(&user.Profile)
 
-	err = s.userRepo.CreateUser(user)
+	start = s.now()
+	err = s.userRepo.CreateUser(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %v", err)
 	}
+	timings.insert = s.now().Sub(start)
 
 	// Publish user created event
+	start = s.now()
 	if s.messageBroker != nil {
-		err = s.messageBroker.PublishUserCreated(user)
+		err = s.messageBroker.PublishUserCreated(ctx, user)
 		if err != nil {
 			// Log error but don't fail registration
-			fmt.Printf("Failed to publish user created event: %v\n", err)
+			slog.ErrorContext(ctx, "Failed to publish user created event", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
 		}
 	}
+	timings.publish = s.now().Sub(start)
 
 	return user, nil
 }
 
 // Login authenticates a user and returns JWT token
 func (s *AuthService) Login(ctx context.Context, email, password string) (string, *models.User, error) {
-	_ = ctx // TODO: use ctx in future
 	if s.userRepo == nil {
 		return "", nil, errors.New("user repository is not initialized")
 	}
 
-	user, err := s.userRepo.GetUserByEmail(email)
+	if err := s.validateCredentialLengths(email, password); err != nil {
+		return "", nil, err
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials: %v", err)
+		// Run a comparison anyway so unknown emails are not revealed by timing
+		verifyPasswordHash(string(dummyPasswordHash()), password)
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
 	}
 
 	// Compare password with hashed password in service layer
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
-		return "", nil, fmt.Errorf("invalid credentials: %v", err)
+	if !verifyPasswordHash(user.Password, password) {
+		s.recordFailedAttempt(ctx, user)
+		return "", nil, fmt.Errorf("%w: password does not match", ErrInvalidCredentials)
+	}
+	// Checked after the password so suspension is only revealed to the account owner
+	if user.SuspendedAt != nil {
+		return "", nil, ErrAccountSuspended
+	}
+	if s.rehashOnLogin && s.passwordHasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user, password)
 	}
 
 	token, err := s.GenerateJWTToken(user)
 	if err != nil {
 		return "", nil, err
 	}
+	if s.features.Enabled(features.LastLoginTracking) {
+		s.touchLastLogin(ctx, user)
+	}
+	if user.FailedAttempts > 0 {
+		s.resetFailedAttempts(ctx, user)
+	}
 
 	return token, user, nil
 }
 
-// ValidateToken validates JWT token and returns claims
-func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
-	_ = ctx // TODO: use ctx in future
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// recordFailedAttempt counts a wrong password against user
+// Failures are logged and never change the login outcome
+func (s *AuthService) recordFailedAttempt(ctx context.Context, user *models.User) {
+	count, err := s.userRepo.IncrementFailedAttempts(ctx, user.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record failed login attempt", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		return
+	}
+	user.FailedAttempts = count
+}
+
+// resetFailedAttempts clears the failed login counter of user after a successful login
+// Failures are logged and never fail the login
+func (s *AuthService) resetFailedAttempts(ctx context.Context, user *models.User) {
+	if err := s.userRepo.ResetFailedAttempts(ctx, user.ID); err != nil {
+		slog.WarnContext(ctx, "Failed to reset failed login attempts", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		return
+	}
+	user.FailedAttempts = 0
+}
+
+// touchLastLogin records the login time of user
+// Failures are logged and never fail the login, the timestamp is informational
+func (s *AuthService) touchLastLogin(ctx context.Context, user *models.User) {
+	at := s.now()
+	if err := s.userRepo.TouchLastLogin(ctx, user.ID, at); err != nil {
+		slog.WarnContext(ctx, "Failed to record last login", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		return
+	}
+	user.LastLoginAt = &at
+}
+
+// VerifyPassword re-checks a user's password without issuing a token, e.g. for step-up auth
+// It returns nil on match and ErrInvalidCredentials for an unknown user or a wrong password
+func (s *AuthService) VerifyPassword(ctx context.Context, userID, password string) error {
+	if s.userRepo == nil {
+		return errors.New("user repository is not initialized")
+	}
+
+	if len(password) > s.maxPasswordLength {
+		return ErrInvalidCredentials
+	}
+
+	hash := string(dummyPasswordHash())
+	found := false
+	if id, err := uuid.Parse(userID); err == nil {
+		if user, err := s.userRepo.GetUserByID(ctx, id); err == nil {
+			hash = user.Password
+			found = true
+		}
+	}
+
+	// Always run the comparison so unknown users are not revealed by timing
+	if !verifyPasswordHash(hash, password) || !found {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// verifyPasswordHash checks password against a hash of any known algorithm
+func verifyPasswordHash(encodedHash, password string) bool {
+	for _, hasher := range knownHashers {
+		if hasher.Owns(encodedHash) {
+			return hasher.Verify(encodedHash, password)
 		}
-		return s.JWTSecret, nil
-	})
+	}
+	return false
+}
 
+// rehashPassword upgrades a stored hash to the configured algorithm after a successful login
+// Failures are logged and never fail the login, the old hash keeps working
+func (s *AuthService) rehashPassword(ctx context.Context, user *models.User, password string) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err == nil {
+		err = s.userRepo.UpdatePassword(ctx, user.ID, hash)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to rehash password", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		return
+	}
+	user.Password = hash
+}
+
+// RevokeAllSessions invalidates every token issued to the user so far and emits user.sessions_revoked
+// Tokens are stateless, so revocation records a timestamp that ValidateToken checks the token's iat against
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID string) error {
+	user, err := s.userForAdminAction(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	at := s.now()
+	if err := s.userRepo.RevokeSessions(ctx, user.ID, at); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	user.SessionsRevokedAt = &at
+	s.revocations.put(user.ID.String(), &at, at)
+	slog.InfoContext(ctx, "Sessions revoked", slog.String("user_id", user.ID.String()))
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserSessionsRevoked(ctx, user); err != nil {
+			slog.WarnContext(ctx, "Failed to publish user sessions revoked event", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// SuspendUser blocks logins of the user until UnsuspendUser and emits user.suspended
+// Suspending a suspended user keeps the original suspension time. Tokens already issued
+// stay valid until they expire, RevokeAllSessions ends them
+func (s *AuthService) SuspendUser(ctx context.Context, userID string) error {
+	user, err := s.userForAdminAction(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt != nil {
+		return nil
+	}
+
+	at := s.now()
+	if err := s.userRepo.SuspendUser(ctx, user.ID, at); err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	user.SuspendedAt = &at
+	slog.InfoContext(ctx, "User suspended", slog.String("user_id", user.ID.String()), slog.Time("suspended_at", at))
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserSuspended(ctx, user); err != nil {
+			slog.WarnContext(ctx, "Failed to publish user suspended event", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// UnsuspendUser lifts the user's suspension and emits user.unsuspended, active users are left as is
+func (s *AuthService) UnsuspendUser(ctx context.Context, userID string) error {
+	user, err := s.userForAdminAction(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.SuspendedAt == nil {
+		return nil
+	}
+
+	if err := s.userRepo.UnsuspendUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	suspendedAt := *user.SuspendedAt
+	user.SuspendedAt = nil
+	slog.InfoContext(ctx, "User unsuspended", slog.String("user_id", user.ID.String()), slog.Time("suspended_at", suspendedAt))
+
+	if s.messageBroker != nil {
+		if err := s.messageBroker.PublishUserUnsuspended(ctx, user); err != nil {
+			slog.WarnContext(ctx, "Failed to publish user unsuspended event", slog.String("user_id", user.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// ResetFailedAttempts clears the user's failed login counter on behalf of support
+// A user without failed attempts is left as is, every reset is logged with the cleared count for audit
+func (s *AuthService) ResetFailedAttempts(ctx context.Context, userID string) error {
+	user, err := s.userForAdminAction(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.FailedAttempts == 0 {
+		return nil
+	}
+
+	if err := s.userRepo.ResetFailedAttempts(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to reset failed login attempts: %w", err)
+	}
+	cleared := user.FailedAttempts
+	user.FailedAttempts = 0
+	slog.InfoContext(ctx, "Failed login attempts reset", slog.String("user_id", user.ID.String()), slog.Int("failed_attempts", cleared))
+
+	return nil
+}
+
+// userForAdminAction loads the user targeted by an account management call
+func (s *AuthService) userForAdminAction(ctx context.Context, userID string) (*models.User, error) {
+	if s.userRepo == nil {
+		return nil, errors.New("user repository is not initialized")
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed user id: %v", ErrInvalidArgument, err)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// ValidateToken validates JWT token and returns the claims issued by this service
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := s.parseToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotRevoked(ctx, claims.UserID, claims.IssuedAt); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// TokenResult is the outcome of validating one token with ValidateTokens
+// Exactly one of Claims and Err is set
+type TokenResult struct {
+	Claims *Claims
+	Err    error
+}
+
+// ValidateTokens validates each token like ValidateToken and returns the results in the order of tokens
+// A bad token only fails its own result. Revocation is looked up once per user for the whole batch,
+// so many tokens of the same user cost a single query
+func (s *AuthService) ValidateTokens(ctx context.Context, tokens []string) []TokenResult {
+	type revocation struct {
+		at  *time.Time
+		err error
+	}
+	revocations := make(map[string]revocation)
+
+	results := make([]TokenResult, len(tokens))
+	for i, token := range tokens {
+		claims := &Claims{}
+		if err := s.parseToken(token, claims); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if s.userRepo != nil {
+			r, ok := revocations[claims.UserID]
+			if !ok {
+				r.at, r.err = s.sessionsRevokedAt(ctx, claims.UserID)
+				revocations[claims.UserID] = r
+			}
+			if r.err != nil {
+				results[i].Err = r.err
+				continue
+			}
+			if err := checkIssuedAfterRevocation(r.at, claims.IssuedAt); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		results[i].Claims = claims
+	}
+	return results
+}
+
+// ValidateTokenRaw validates JWT token like ValidateToken and returns every claim it carries
+func (s *AuthService) ValidateTokenRaw(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if err := s.parseToken(tokenString, claims); err != nil {
+		return nil, err
+	}
+	userID, _ := claims["user_id"].(string)
+	issuedAt, err := claims.GetIssuedAt()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %v", err)
 	}
+	if err := s.checkNotRevoked(ctx, userID, issuedAt); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+// checkNotRevoked rejects a token issued before the last session revocation of its user
+// iat has second precision, so a token issued within the revocation second stays valid
+// Tokens without iat predate revocation support and are rejected once the user revokes sessions
+// Lookup failures reject the token, and without a repository there is nothing to check against
+func (s *AuthService) checkNotRevoked(ctx context.Context, userID string, issuedAt *jwt.NumericDate) error {
+	if s.userRepo == nil {
+		return nil
 	}
 
-	return nil, errors.New("invalid token")
+	revokedAt, err := s.sessionsRevokedAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return checkIssuedAfterRevocation(revokedAt, issuedAt)
+}
+
+// sessionsRevokedAt looks up when the sessions of the token subject userID were last revoked
+// Successful lookups are cached for revocationCacheTTL, failures are not
+func (s *AuthService) sessionsRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
+	now := s.now()
+	if revokedAt, ok := s.revocations.get(userID, now); ok {
+		return revokedAt, nil
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token subject: %v", err)
+	}
+	revokedAt, err := s.userRepo.SessionsRevokedAt(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	s.revocations.put(userID, revokedAt, now)
+	return revokedAt, nil
+}
+
+// checkIssuedAfterRevocation rejects a token issued before revokedAt, compared in whole seconds, see checkNotRevoked
+func checkIssuedAfterRevocation(revokedAt *time.Time, issuedAt *jwt.NumericDate) error {
+	if revokedAt != nil && (issuedAt == nil || issuedAt.Unix() < revokedAt.Unix()) {
+		return errors.New("token has been revoked")
+	}
+	return nil
+}
+
+// parseToken verifies the signature and standard claims of tokenString and decodes its claims into claims
+func (s *AuthService) parseToken(tokenString string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.verificationKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse token: %v", err)
+	}
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// verificationKey selects the secret for a token by its kid header
+// Tokens without a kid were issued before key rotation and are verified with the primary secret
+func (s *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == s.JWTKeyID {
+		return s.JWTSecret, nil
+	}
+	if secret, ok := s.verificationKeys[kid]; ok {
+		return secret, nil
+	}
+	return nil, fmt.Errorf("unknown key id: %s", kid)
 }
 
 // GenerateJWTToken generates JWT token for user
@@ -138,12 +710,24 @@ func (s *AuthService) GenerateJWTToken(user *models.User) (string, error) {
 		return "", errors.New("JWT secret is not configured")
 	}
 
+	now := s.now()
 	claims := jwt.MapClaims{
 		"email":   user.Email,
 		"user_id": user.ID.String(),
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(24 * time.Hour).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.JWTSecret)
+	if s.JWTKeyID != "" {
+		token.Header["kid"] = s.JWTKeyID
+	}
+	signed, err := token.SignedString(s.JWTSecret)
+	if err != nil {
+		return "", err
+	}
+
+	metrics.TokensIssued.WithLabelValues(TokenTypeAccess).Inc()
+	slog.Info("Token issued", slog.String("user_id", user.ID.String()), slog.String("token_type", TokenTypeAccess))
+	return signed, nil
 }
@@ -2,18 +2,26 @@ package services_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Koshsky/subs-service/auth-service/internal/config"
+	"github.com/Koshsky/subs-service/auth-service/internal/features"
+	"github.com/Koshsky/subs-service/auth-service/internal/logging/logtest"
 	messagingMocks "github.com/Koshsky/subs-service/auth-service/internal/messaging/mocks"
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
 	"github.com/Koshsky/subs-service/auth-service/internal/models"
 	repositoryMocks "github.com/Koshsky/subs-service/auth-service/internal/repositories/mocks"
 	"github.com/Koshsky/subs-service/auth-service/internal/services"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"golang.org/x/crypto/bcrypt"
@@ -64,13 +72,13 @@ func (suite *AuthServiceTestSuite) SetupTest() {
 
 // mockUserExists mock userRepo.UserExists(email)
 func (suite *AuthServiceTestSuite) mockUserExists(email string, exists bool, err error) {
-	suite.mockUserRepo.On("UserExists", email).Return(exists, err)
+	suite.mockUserRepo.On("UserExists", mock.Anything, email).Return(exists, err)
 }
 
 // mockCreateUser mock userRepo.CreateUser(&user)
 func (suite *AuthServiceTestSuite) mockCreateUser(err error) {
-	suite.mockUserRepo.On("CreateUser", mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
-		user := args.Get(0).(*models.User)
+	suite.mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		user := args.Get(1).(*models.User)
 		if user.ID == uuid.Nil {
 			user.ID = uuid.New()
 		}
@@ -79,12 +87,27 @@ func (suite *AuthServiceTestSuite) mockCreateUser(err error) {
 
 // mockGetUserByEmail mock userRepo.GetUserByEmail(email)
 func (suite *AuthServiceTestSuite) mockGetUserByEmail(email string, user *models.User, err error) {
-	suite.mockUserRepo.On("GetUserByEmail", email).Return(user, err)
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(user, err)
 }
 
-// mockPublishUserCreated mock messageBroker.PublishUserCreated(&user)
+// mockTouchLastLogin mock userRepo.TouchLastLogin(id, any time)
+func (suite *AuthServiceTestSuite) mockTouchLastLogin(id uuid.UUID, err error) {
+	suite.mockUserRepo.On("TouchLastLogin", mock.Anything, id, mock.AnythingOfType("time.Time")).Return(err)
+}
+
+// mockIncrementFailedAttempts mock userRepo.IncrementFailedAttempts(id)
+func (suite *AuthServiceTestSuite) mockIncrementFailedAttempts(id uuid.UUID, count int, err error) {
+	suite.mockUserRepo.On("IncrementFailedAttempts", mock.Anything, id).Return(count, err)
+}
+
+// mockSessionsRevokedAt mock userRepo.SessionsRevokedAt(id), nil revokedAt means never revoked
+func (suite *AuthServiceTestSuite) mockSessionsRevokedAt(id uuid.UUID, revokedAt *time.Time, err error) {
+	suite.mockUserRepo.On("SessionsRevokedAt", mock.Anything, id).Return(revokedAt, err)
+}
+
+// mockPublishUserCreated mock messageBroker.PublishUserCreated(ctx, &user)
 func (suite *AuthServiceTestSuite) mockPublishUserCreated(err error) {
-	suite.mockMessageBroker.On("PublishUserCreated", mock.AnythingOfType("*models.User")).Return(err)
+	suite.mockMessageBroker.On("PublishUserCreated", mock.Anything, mock.AnythingOfType("*models.User")).Return(err)
 }
 
 // ===== REGISTER TESTS =====
@@ -108,6 +131,132 @@ func (suite *AuthServiceTestSuite) TestRegister_Success() {
 	suite.Require().NoError(bcrypt.CompareHashAndPassword([]byte(returnedUser.Password), []byte(suite.password)))
 }
 
+func (suite *AuthServiceTestSuite) TestRegister_LogsStepTimings() {
+	// Arrange
+	defaultLogger := slog.Default()
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
+	// Every reading advances the clock, so each step spans a known, positive duration
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.authService.SetClock(func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	})
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	entry := logs.FindMessage("User registered")
+	suite.Require().NotNil(entry)
+	suite.Equal(user.ID.String(), entry["user_id"])
+	for _, field := range []string{"validate_ms", "hash_ms", "insert_ms", "publish_ms"} {
+		suite.Require().Contains(entry, field)
+		suite.Equal(1.0, entry[field], field)
+	}
+}
+
+func (suite *AuthServiceTestSuite) TestRegisterWithProfile_Success() {
+	// Arrange
+	profile := &models.Profile{DisplayName: "John Doe", Locale: "en-US", Timezone: "Europe/Berlin"}
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	returnedUser, err := suite.authService.RegisterWithProfile(suite.ctx, suite.email, suite.password, profile)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NotNil(returnedUser)
+	suite.Equal(*profile, returnedUser.Profile)
+	suite.mockUserRepo.AssertCalled(suite.T(), "CreateUser", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+		return user.Profile == *profile
+	}))
+}
+
+func (suite *AuthServiceTestSuite) TestRegisterWithProfile_InvalidProfile() {
+	tests := []struct {
+		name    string
+		profile *models.Profile
+		errText string
+	}{
+		{
+			name:    "Unknown timezone",
+			profile: &models.Profile{Timezone: "Mars/Olympus_Mons"},
+			errText: "unknown timezone: Mars/Olympus_Mons",
+		},
+		{
+			name:    "Local timezone",
+			profile: &models.Profile{Timezone: "Local"},
+			errText: "unknown timezone: Local",
+		},
+		{
+			name:    "Unsupported locale",
+			profile: &models.Profile{Locale: "xx-YY"},
+			errText: "unsupported locale: xx-YY",
+		},
+		{
+			name:    "Display name too long",
+			profile: &models.Profile{DisplayName: strings.Repeat("a", services.MaxDisplayNameLength+1)},
+			errText: "display name must be at most",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Act
+			returnedUser, err := suite.authService.RegisterWithProfile(suite.ctx, suite.email, suite.password, tt.profile)
+
+			// Assert
+			suite.Require().Error(err)
+			suite.Nil(returnedUser)
+			suite.Contains(err.Error(), "invalid profile")
+			suite.Contains(err.Error(), tt.errText)
+		})
+	}
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestRegisterWithProfile_NilProfile() {
+	// Arrange
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	returnedUser, err := suite.authService.RegisterWithProfile(suite.ctx, suite.email, suite.password, nil)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.email, returnedUser.Email)
+	suite.Equal(models.Profile{}, returnedUser.Profile)
+}
+
+func (suite *AuthServiceTestSuite) TestRegisterWithProfile_AppliesDefaults() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", DefaultLocale: "ru-RU", DefaultTimezone: "Europe/Moscow"}
+	suite.authService = services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	withTimezone, err := suite.authService.RegisterWithProfile(suite.ctx, suite.email, suite.password, &models.Profile{Timezone: "Asia/Tokyo"})
+	suite.Require().NoError(err)
+	withoutProfile, err := suite.authService.Register(suite.ctx, suite.email, suite.password)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(models.Profile{Locale: "ru-RU", Timezone: "Asia/Tokyo"}, withTimezone.Profile)
+	suite.Equal(models.Profile{Locale: "ru-RU", Timezone: "Europe/Moscow"}, withoutProfile.Profile)
+}
+
 func (suite *AuthServiceTestSuite) TestRegister_NilUserRepository() {
 	// Arrange
 	suite.authService = services.NewAuthService(nil, suite.mockMessageBroker, suite.config)
@@ -165,6 +314,10 @@ func (suite *AuthServiceTestSuite) TestRegister_CreateUserError() {
 
 func (suite *AuthServiceTestSuite) TestRegister_PublishError() {
 	// Arrange
+	defaultLogger := slog.Default()
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
 	expectedError := errors.New("publish error")
 
 	suite.mockUserExists(suite.email, false, nil)
@@ -178,6 +331,11 @@ func (suite *AuthServiceTestSuite) TestRegister_PublishError() {
 	suite.Require().NoError(err) // Register should succeed even if RabbitMQ fails
 	suite.Require().NotNil(user)
 	suite.Equal(suite.email, user.Email)
+	entry := logs.FindMessage("Failed to publish user created event")
+	suite.Require().NotNil(entry)
+	suite.Equal("ERROR", entry["level"])
+	suite.Equal("publish error", entry["error"])
+	suite.Equal(user.ID.String(), entry["user_id"])
 }
 
 func (suite *AuthServiceTestSuite) TestRegister_PasswordHashingError() {
@@ -194,11 +352,199 @@ func (suite *AuthServiceTestSuite) TestRegister_PasswordHashingError() {
 	suite.Contains(err.Error(), "failed to hash password")
 }
 
+func (suite *AuthServiceTestSuite) TestRegister_FieldLengthLimits() {
+	localPart := func(total int) string {
+		return strings.Repeat("a", total-len("@example.com")) + "@example.com"
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		errText  string
+	}{
+		{
+			name:     "Email over limit",
+			email:    localPart(services.DefaultMaxEmailLength + 1),
+			password: suite.password,
+			errText:  "email must be at most 254 bytes",
+		},
+		{
+			name:     "Password over limit",
+			email:    suite.email,
+			password: strings.Repeat("a", services.DefaultMaxPasswordLength+1),
+			errText:  "password must be at most 1024 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Act
+			user, err := suite.authService.Register(suite.ctx, tt.email, tt.password)
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidArgument)
+			suite.Nil(user)
+			suite.Contains(err.Error(), tt.errText)
+		})
+	}
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UserExists", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_FieldLengthBoundaries() {
+	// Arrange
+	email := strings.Repeat("a", services.DefaultMaxEmailLength-len("@example.com")) + "@example.com"
+	password := strings.Repeat("a", services.DefaultMaxPasswordLength)
+	authService := suite.argon2Service(false)
+	suite.mockUserExists(email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := authService.Register(suite.ctx, email, password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(email, user.Email)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_ConfiguredLengthLimits() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", MaxEmailLength: 10, MaxPasswordLength: 8}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+
+	// Act
+	_, emailErr := authService.Register(suite.ctx, "test@example.com", "short")
+	_, passwordErr := authService.Register(suite.ctx, "a@b.co", "too-long-password")
+
+	// Assert
+	suite.Require().ErrorIs(emailErr, services.ErrInvalidArgument)
+	suite.Require().ErrorIs(passwordErr, services.ErrInvalidArgument)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_AllowedEmailDomain() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", AllowedEmailDomains: []string{"corp.example", "Example.com"}}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	email := "test@EXAMPLE.com"
+	suite.mockUserExists(email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := authService.Register(suite.ctx, email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(email, user.Email)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_DisallowedEmailDomain() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", AllowedEmailDomains: []string{"example.com"}}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+
+	for _, email := range []string{"test@other.org", "test@mail.example.com", "test@example.com.evil.org", "no-at-sign"} {
+		suite.Run(email, func() {
+			// Act
+			user, err := authService.Register(suite.ctx, email, suite.password)
+
+			// Assert
+			suite.Require().ErrorIs(err, services.ErrInvalidArgument)
+			suite.Contains(err.Error(), "email domain is not allowed")
+			suite.Nil(user)
+		})
+	}
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UserExists", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_EmptyAllowedDomainsAllowsAll() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", AllowedEmailDomains: []string{}}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	email := "test@anywhere.org"
+	suite.mockUserExists(email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := authService.Register(suite.ctx, email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(email, user.Email)
+}
+
+// ===== BOOTSTRAP ADMIN TESTS =====
+
+func (suite *AuthServiceTestSuite) TestBootstrapAdmin_EmptyTableCreatesAdmin() {
+	// Arrange
+	suite.mockUserRepo.On("HasUsers", mock.Anything).Return(false, nil)
+	var created *models.User
+	suite.mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.User)
+	}).Return(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	ok, err := suite.authService.BootstrapAdmin(suite.ctx, "admin@example.com", suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(ok)
+	suite.Require().NotNil(created)
+	suite.Equal("admin@example.com", created.Email)
+	suite.Equal(models.RoleAdmin, created.Role)
+	suite.Require().NoError(bcrypt.CompareHashAndPassword([]byte(created.Password), []byte(suite.password)))
+}
+
+func (suite *AuthServiceTestSuite) TestBootstrapAdmin_NonEmptyTableSkips() {
+	// Arrange
+	suite.mockUserRepo.On("HasUsers", mock.Anything).Return(true, nil)
+
+	// Act
+	ok, err := suite.authService.BootstrapAdmin(suite.ctx, "admin@example.com", suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.False(ok)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestBootstrapAdmin_HasUsersError() {
+	// Arrange
+	suite.mockUserRepo.On("HasUsers", mock.Anything).Return(false, errors.New("connection refused"))
+
+	// Act
+	ok, err := suite.authService.BootstrapAdmin(suite.ctx, "admin@example.com", suite.password)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.False(ok)
+	suite.Contains(err.Error(), "failed to check for existing users")
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_AssignsUserRole() {
+	// Arrange
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := suite.authService.Register(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(models.RoleUser, user.Role)
+}
+
 // ===== LOGIN TESTS =====
 
 func (suite *AuthServiceTestSuite) TestLogin_Success() {
 	// Arrange
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
 
 	// Act
 	token, returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
@@ -212,8 +558,8 @@ func (suite *AuthServiceTestSuite) TestLogin_Success() {
 	claims, err := suite.authService.ValidateToken(suite.ctx, token)
 	suite.Require().NoError(err)
 	suite.Require().NotNil(claims)
-	suite.Equal(returnedUser.ID.String(), claims["user_id"])
-	suite.Equal(returnedUser.Email, claims["email"])
+	suite.Equal(returnedUser.ID.String(), claims.UserID)
+	suite.Equal(returnedUser.Email, claims.Email)
 }
 
 func (suite *AuthServiceTestSuite) TestLogin_NilUserRepository() {
@@ -248,6 +594,7 @@ func (suite *AuthServiceTestSuite) TestLogin_ValidationError() {
 func (suite *AuthServiceTestSuite) TestLogin_InvalidPassword() {
 	// Arrange
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 1, nil)
 
 	// Act
 	token, returnedUser, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
@@ -259,6 +606,89 @@ func (suite *AuthServiceTestSuite) TestLogin_InvalidPassword() {
 	suite.Contains(err.Error(), "invalid credentials")
 }
 
+func (suite *AuthServiceTestSuite) TestLogin_InvalidPasswordCountsFailedAttempt() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 3, nil)
+
+	// Act
+	_, _, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+	suite.Equal(3, suite.testUser.FailedAttempts)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ResetFailedAttempts", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_FailedAttemptStoreFailureIsNotFatal() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 0, errors.New("connection lost"))
+
+	// Act
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+	suite.Empty(token)
+	suite.Nil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_SuccessResetsFailedAttempts() {
+	// Arrange
+	suite.testUser.FailedAttempts = 2
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	suite.mockUserRepo.On("ResetFailedAttempts", mock.Anything, suite.testUser.ID).Return(nil)
+
+	// Act
+	_, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Zero(user.FailedAttempts)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_SuccessWithoutFailedAttemptsSkipsReset() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+
+	// Act
+	_, _, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ResetFailedAttempts", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_ResetFailureIsNotFatal() {
+	// Arrange
+	suite.testUser.FailedAttempts = 2
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	suite.mockUserRepo.On("ResetFailedAttempts", mock.Anything, suite.testUser.ID).Return(errors.New("connection lost"))
+
+	// Act
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.Equal(2, user.FailedAttempts)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_PasswordOverLimit() {
+	// Act
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, strings.Repeat("a", services.DefaultMaxPasswordLength+1))
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidArgument)
+	suite.Empty(token)
+	suite.Nil(user)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetUserByEmail", mock.Anything, mock.Anything)
+}
+
 func (suite *AuthServiceTestSuite) TestLogin_TokenGenerationError() {
 	// Arrange
 	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
@@ -277,10 +707,91 @@ func (suite *AuthServiceTestSuite) TestLogin_TokenGenerationError() {
 	suite.Contains(err.Error(), "JWT secret is not configured")
 }
 
+func (suite *AuthServiceTestSuite) TestLogin_TouchesLastLoginWithClockTime() {
+	// Arrange
+	loginTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.authService.SetClock(func() time.Time { return loginTime })
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockUserRepo.On("TouchLastLogin", mock.Anything, suite.testUser.ID, loginTime).Return(nil)
+
+	// Act
+	_, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().NotNil(user.LastLoginAt)
+	suite.Equal(loginTime, *user.LastLoginAt)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_TouchFailureDoesNotFailLogin() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, errors.New("connection lost"))
+
+	// Act
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.Nil(user.LastLoginAt)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_FailedLoginDoesNotTouchLastLogin() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 1, nil)
+
+	// Act
+	_, _, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "TouchLastLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_LastLoginTrackingFlag() {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "Tracking enabled", enabled: true},
+		{name: "Tracking disabled", enabled: false},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			stored := *suite.testUser
+			repo := repositoryMocks.NewIUserRepository(suite.T())
+			repo.On("GetUserByEmail", mock.Anything, suite.email).Return(&stored, nil)
+			if tt.enabled {
+				repo.On("TouchLastLogin", mock.Anything, suite.testUser.ID, mock.AnythingOfType("time.Time")).Return(nil)
+			}
+			cfg := *suite.config
+			cfg.Features = features.New(map[string]bool{features.LastLoginTracking: tt.enabled})
+			service := services.NewAuthService(repo, suite.mockMessageBroker, &cfg)
+
+			// Act
+			_, user, err := service.Login(suite.ctx, suite.email, suite.password)
+
+			// Assert
+			suite.Require().NoError(err)
+			if tt.enabled {
+				suite.NotNil(user.LastLoginAt)
+			} else {
+				suite.Nil(user.LastLoginAt)
+				repo.AssertNotCalled(suite.T(), "TouchLastLogin", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 // ===== JWT TOKEN TESTS =====
 
 func (suite *AuthServiceTestSuite) TestGenerateJWTToken_Success() {
 	// Arrange
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
 
 	// Act
 	token, err := suite.authService.GenerateJWTToken(suite.testUser)
@@ -293,8 +804,35 @@ func (suite *AuthServiceTestSuite) TestGenerateJWTToken_Success() {
 	claims, err := suite.authService.ValidateToken(suite.ctx, token)
 	suite.Require().NoError(err)
 	suite.Require().NotNil(claims)
-	suite.Equal(suite.testUser.ID.String(), claims["user_id"])
-	suite.Equal(suite.testUser.Email, claims["email"])
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+	suite.Equal(suite.testUser.Email, claims.Email)
+}
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_RecordsIssuance() {
+	// Arrange
+	defaultLogger := slog.Default()
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
+	issued := metrics.TokensIssued.WithLabelValues(services.TokenTypeAccess)
+	before := testutil.ToFloat64(issued)
+
+	// Act
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(before+1, testutil.ToFloat64(issued))
+	entry := logs.FindMessage("Token issued")
+	suite.Require().NotNil(entry)
+	suite.Equal("INFO", entry["level"])
+	suite.Equal(suite.testUser.ID.String(), entry["user_id"])
+	suite.Equal(services.TokenTypeAccess, entry["token_type"])
+	for _, logged := range logs.Entries() {
+		for key, value := range logged {
+			suite.NotContains(fmt.Sprint(value), token, key)
+		}
+	}
 }
 
 func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilUser() {
@@ -325,6 +863,7 @@ func (suite *AuthServiceTestSuite) TestGenerateJWTToken_NilSecret() {
 
 func (suite *AuthServiceTestSuite) TestValidateToken_Success() {
 	// Arrange
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
 	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
 
 	// Act
@@ -333,19 +872,95 @@ func (suite *AuthServiceTestSuite) TestValidateToken_Success() {
 	// Assert
 	suite.Require().NoError(err)
 	suite.Require().NotNil(claims)
-	suite.Equal(suite.testUser.ID.String(), claims["user_id"])
-	suite.Equal(suite.testUser.Email, claims["email"])
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+	suite.Equal(suite.testUser.Email, claims.Email)
 }
 
-func (suite *AuthServiceTestSuite) TestValidateToken_InvalidClaims() {
+func (suite *AuthServiceTestSuite) TestValidateTokenRaw_MatchesTypedClaims() {
 	// Arrange
-	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     expiresAt,
+		"tenant":  "acme",
+	})
+	signed, err := token.SignedString(suite.authService.JWTSecret)
+	suite.Require().NoError(err)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
 
-	parts := strings.Split(token, ".")
-	if len(parts) >= 2 {
-		parts[1] = "invalid-payload"
-	}
-	corruptedToken := strings.Join(parts, ".")
+	// Act
+	typed, typedErr := suite.authService.ValidateToken(suite.ctx, signed)
+	raw, rawErr := suite.authService.ValidateTokenRaw(suite.ctx, signed)
+
+	// Assert
+	suite.Require().NoError(typedErr)
+	suite.Require().NoError(rawErr)
+	suite.Equal(typed.UserID, raw["user_id"])
+	suite.Equal(typed.Email, raw["email"])
+	rawExpiry, err := raw.GetExpirationTime()
+	suite.Require().NoError(err)
+	suite.Equal(typed.ExpiresAt.Unix(), rawExpiry.Unix())
+	suite.Equal(expiresAt, typed.ExpiresAt.Unix())
+	suite.Equal("acme", raw["tenant"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateTokenRaw_SharesVerification() {
+	// Arrange
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(-time.Hour).Unix(),
+	})
+	expiredToken, _ := expired.SignedString(suite.authService.JWTSecret)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	forgedToken, _ := forged.SignedString(suite.wrongSecret)
+
+	for name, token := range map[string]string{"Expired": expiredToken, "Forged": forgedToken, "Malformed": "not-a-token"} {
+		suite.Run(name, func() {
+			// Act
+			typed, typedErr := suite.authService.ValidateToken(suite.ctx, token)
+			raw, rawErr := suite.authService.ValidateTokenRaw(suite.ctx, token)
+
+			// Assert
+			suite.Require().Error(typedErr)
+			suite.Require().Error(rawErr)
+			suite.Nil(typed)
+			suite.Nil(raw)
+			suite.Equal(typedErr.Error(), rawErr.Error())
+		})
+	}
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_MistypedClaim() {
+	// Arrange
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": 123,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	signed, _ := token.SignedString(suite.authService.JWTSecret)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, signed)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "failed to parse token")
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_InvalidClaims() {
+	// Arrange
+	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+
+	parts := strings.Split(token, ".")
+	if len(parts) >= 2 {
+		parts[1] = "invalid-payload"
+	}
+	corruptedToken := strings.Join(parts, ".")
 
 	// Act
 	claims, err := suite.authService.ValidateToken(suite.ctx, corruptedToken)
@@ -376,11 +991,11 @@ func (suite *AuthServiceTestSuite) TestValidateToken_ForgedToken() {
 	forgedToken, _ := token.SignedString(wrongSecret)
 
 	// Act
-	claims, err := suite.authService.ValidateToken(suite.ctx, forgedToken)
+	validatedClaims, err := suite.authService.ValidateToken(suite.ctx, forgedToken)
 
 	// Assert
 	suite.Require().Error(err)
-	suite.Require().Nil(claims)
+	suite.Require().Nil(validatedClaims)
 	suite.Contains(err.Error(), "signature is invalid")
 }
 
@@ -395,14 +1010,915 @@ func (suite *AuthServiceTestSuite) TestValidateToken_ExpiredToken() {
 	expiredToken, _ := token.SignedString(suite.authService.JWTSecret)
 
 	// Act
-	claims, err := suite.authService.ValidateToken(suite.ctx, expiredToken)
+	validatedClaims, err := suite.authService.ValidateToken(suite.ctx, expiredToken)
 
 	// Assert
 	suite.Require().Error(err)
-	suite.Require().Nil(claims)
+	suite.Require().Nil(validatedClaims)
 	suite.Contains(err.Error(), "token is expired")
 }
 
+// ===== VALIDATE TOKENS TESTS =====
+
+func (suite *AuthServiceTestSuite) TestValidateTokens_MixedBatch() {
+	// Arrange
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
+	valid, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	suite.authService.SetClock(func() time.Time { return time.Now().Add(-48 * time.Hour) })
+	expired, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	suite.authService.SetClock(nil)
+
+	// Act
+	results := suite.authService.ValidateTokens(suite.ctx, []string{valid, expired, "not.a.token", valid})
+
+	// Assert
+	suite.Require().Len(results, 4)
+	suite.Require().NoError(results[0].Err)
+	suite.Equal(suite.testUser.ID.String(), results[0].Claims.UserID)
+	suite.Require().Error(results[1].Err)
+	suite.Contains(results[1].Err.Error(), "token is expired")
+	suite.Nil(results[1].Claims)
+	suite.Require().Error(results[2].Err)
+	suite.Contains(results[2].Err.Error(), "failed to parse token")
+	suite.Nil(results[2].Claims)
+	suite.Require().NoError(results[3].Err)
+	suite.Equal(suite.testUser.Email, results[3].Claims.Email)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "SessionsRevokedAt", 1)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateTokens_RevokedUser() {
+	// Arrange
+	issuedAt := time.Now().Add(-time.Hour)
+	suite.authService.SetClock(func() time.Time { return issuedAt })
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	revokedAt := issuedAt.Add(time.Minute)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, &revokedAt, nil)
+
+	// Act
+	results := suite.authService.ValidateTokens(suite.ctx, []string{token})
+
+	// Assert
+	suite.Require().Len(results, 1)
+	suite.Require().Error(results[0].Err)
+	suite.Contains(results[0].Err.Error(), "token has been revoked")
+	suite.Nil(results[0].Claims)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateTokens_Empty() {
+	// Act
+	results := suite.authService.ValidateTokens(suite.ctx, nil)
+
+	// Assert
+	suite.Empty(results)
+}
+
+// ===== SESSION REVOCATION TESTS =====
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_Success() {
+	// Arrange
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.authService.SetClock(func() time.Time { return revokedAt })
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("RevokeSessions", mock.Anything, suite.testUser.ID, revokedAt).Return(nil)
+	suite.mockMessageBroker.On("PublishUserSessionsRevoked", mock.Anything, mock.MatchedBy(func(user *models.User) bool {
+		return user.ID == suite.testUser.ID && user.SessionsRevokedAt != nil && user.SessionsRevokedAt.Equal(revokedAt)
+	})).Return(nil)
+
+	// Act
+	err := suite.authService.RevokeAllSessions(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_InvalidatesIssuedTokens() {
+	// Arrange
+	issuedAt := time.Now().Add(-time.Hour)
+	suite.authService.SetClock(func() time.Time { return issuedAt })
+	first, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	second, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	revokedAt := issuedAt.Add(time.Minute)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, &revokedAt, nil)
+
+	// Act
+	_, firstErr := suite.authService.ValidateToken(suite.ctx, first)
+	_, secondErr := suite.authService.ValidateTokenRaw(suite.ctx, second)
+
+	// Assert
+	suite.Require().Error(firstErr)
+	suite.Contains(firstErr.Error(), "token has been revoked")
+	suite.Require().Error(secondErr)
+	suite.Contains(secondErr.Error(), "token has been revoked")
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_LaterTokensStayValid() {
+	// Arrange
+	revokedAt := time.Now().Add(-time.Hour)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, &revokedAt, nil)
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_TokenIssuedInRevocationSecondStaysValid() {
+	// Arrange
+	revokedAt := time.Now().Truncate(time.Second).Add(600 * time.Millisecond)
+	suite.authService.SetClock(func() time.Time { return revokedAt.Add(200 * time.Millisecond) })
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, &revokedAt, nil)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_CachesRevocationLookup() {
+	// Arrange
+	now := time.Now()
+	suite.authService.SetClock(func() time.Time { return now })
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
+
+	// Act
+	_, firstErr := suite.authService.ValidateToken(suite.ctx, token)
+	_, secondErr := suite.authService.ValidateToken(suite.ctx, token)
+	now = now.Add(time.Minute)
+	_, expiredErr := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(firstErr)
+	suite.Require().NoError(secondErr)
+	suite.Require().NoError(expiredErr)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "SessionsRevokedAt", 2)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_RevokesCachedTokens() {
+	// Arrange
+	now := time.Now()
+	suite.authService.SetClock(func() time.Time { return now })
+	token, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
+	_, err = suite.authService.ValidateToken(suite.ctx, token)
+	suite.Require().NoError(err)
+	now = now.Add(time.Second)
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("RevokeSessions", mock.Anything, suite.testUser.ID, now).Return(nil)
+	suite.mockMessageBroker.On("PublishUserSessionsRevoked", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+	suite.Require().NoError(suite.authService.RevokeAllSessions(suite.ctx, suite.testUser.ID.String()))
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "token has been revoked")
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "SessionsRevokedAt", 1)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_TokenWithoutIssuedAt() {
+	// Arrange
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	signed, _ := token.SignedString(suite.authService.JWTSecret)
+	revokedAt := time.Now().Add(-time.Hour)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, &revokedAt, nil)
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, signed)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "token has been revoked")
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_LookupFailureRejectsToken() {
+	// Arrange
+	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, errors.New("connection lost"))
+
+	// Act
+	claims, err := suite.authService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "failed to check token revocation")
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_PublishFailureIsNotFatal() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("RevokeSessions", mock.Anything, suite.testUser.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.mockMessageBroker.On("PublishUserSessionsRevoked", mock.Anything, mock.AnythingOfType("*models.User")).Return(errors.New("broker down"))
+
+	// Act
+	err := suite.authService.RevokeAllSessions(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_StoreFailure() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("RevokeSessions", mock.Anything, suite.testUser.ID, mock.AnythingOfType("time.Time")).Return(errors.New("connection lost"))
+
+	// Act
+	err := suite.authService.RevokeAllSessions(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to revoke sessions")
+}
+
+func (suite *AuthServiceTestSuite) TestRevokeAllSessions_MalformedID() {
+	// Act
+	err := suite.authService.RevokeAllSessions(suite.ctx, "not-a-uuid")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidArgument)
+}
+
+// ===== SUSPENSION TESTS =====
+
+func (suite *AuthServiceTestSuite) TestLogin_BlockedWhileSuspended() {
+	// Arrange
+	suspendedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.authService.SetClock(func() time.Time { return suspendedAt })
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("SuspendUser", mock.Anything, suite.testUser.ID, suspendedAt).Return(nil)
+	suite.mockMessageBroker.On("PublishUserSuspended", mock.Anything, suite.testUser).Return(nil)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+
+	// Act
+	suspendErr := suite.authService.SuspendUser(suite.ctx, suite.testUser.ID.String())
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(suspendErr)
+	suite.ErrorIs(err, services.ErrAccountSuspended)
+	suite.Empty(token)
+	suite.Nil(user)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_SuspendedWrongPasswordIsInvalidCredentials() {
+	// Arrange
+	suspendedAt := time.Now()
+	suite.testUser.SuspendedAt = &suspendedAt
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 1, nil)
+
+	// Act
+	_, _, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidCredentials)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_AllowedAfterUnsuspend() {
+	// Arrange
+	suspendedAt := time.Now().Add(-time.Hour)
+	suite.testUser.SuspendedAt = &suspendedAt
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("UnsuspendUser", mock.Anything, suite.testUser.ID).Return(nil)
+	suite.mockMessageBroker.On("PublishUserUnsuspended", mock.Anything, suite.testUser).Return(nil)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+
+	// Act
+	unsuspendErr := suite.authService.UnsuspendUser(suite.ctx, suite.testUser.ID.String())
+	token, user, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(unsuspendErr)
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.Nil(user.SuspendedAt)
+}
+
+func (suite *AuthServiceTestSuite) TestSuspendUser_AlreadySuspendedKeepsTime() {
+	// Arrange
+	suspendedAt := time.Now().Add(-time.Hour)
+	suite.testUser.SuspendedAt = &suspendedAt
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+
+	// Act
+	err := suite.authService.SuspendUser(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suspendedAt, *suite.testUser.SuspendedAt)
+}
+
+func (suite *AuthServiceTestSuite) TestSuspendUser_StoreFailure() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("SuspendUser", mock.Anything, suite.testUser.ID, mock.AnythingOfType("time.Time")).Return(errors.New("connection lost"))
+
+	// Act
+	err := suite.authService.SuspendUser(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to suspend user")
+	suite.Nil(suite.testUser.SuspendedAt)
+}
+
+func (suite *AuthServiceTestSuite) TestSuspendUser_MalformedID() {
+	// Act
+	err := suite.authService.SuspendUser(suite.ctx, "not-a-uuid")
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidArgument)
+}
+
+// ===== FAILED ATTEMPTS RESET TESTS =====
+
+func (suite *AuthServiceTestSuite) TestResetFailedAttempts_ClearsCounterAndLogsAudit() {
+	// Arrange
+	defaultLogger := slog.Default()
+	logger, logs := logtest.NewTestLogger()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
+	suite.testUser.FailedAttempts = 4
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("ResetFailedAttempts", mock.Anything, suite.testUser.ID).Return(nil).Once()
+
+	// Act
+	err := suite.authService.ResetFailedAttempts(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(0, suite.testUser.FailedAttempts)
+	entry := logs.FindMessage("Failed login attempts reset")
+	suite.Require().NotNil(entry)
+	suite.Equal(suite.testUser.ID.String(), entry["user_id"])
+	suite.Equal(4.0, entry["failed_attempts"])
+}
+
+func (suite *AuthServiceTestSuite) TestResetFailedAttempts_NoFailuresIsNoOp() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+
+	// Act
+	err := suite.authService.ResetFailedAttempts(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "ResetFailedAttempts", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestResetFailedAttempts_LoginAfterResetDoesNotResetAgain() {
+	// Arrange
+	suite.testUser.FailedAttempts = 2
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("ResetFailedAttempts", mock.Anything, suite.testUser.ID).Return(nil).Once()
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+
+	// Act
+	resetErr := suite.authService.ResetFailedAttempts(suite.ctx, suite.testUser.ID.String())
+	token, _, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(resetErr)
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "ResetFailedAttempts", 1)
+}
+
+func (suite *AuthServiceTestSuite) TestResetFailedAttempts_StoreFailure() {
+	// Arrange
+	suite.testUser.FailedAttempts = 3
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	suite.mockUserRepo.On("ResetFailedAttempts", mock.Anything, suite.testUser.ID).Return(errors.New("connection lost"))
+
+	// Act
+	err := suite.authService.ResetFailedAttempts(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to reset failed login attempts")
+	suite.Equal(3, suite.testUser.FailedAttempts)
+}
+
+func (suite *AuthServiceTestSuite) TestResetFailedAttempts_MalformedID() {
+	// Act
+	err := suite.authService.ResetFailedAttempts(suite.ctx, "not-a-uuid")
+
+	// Assert
+	suite.ErrorIs(err, services.ErrInvalidArgument)
+}
+
+// ===== PASSWORD HASHING TESTS =====
+
+// argon2Service returns a service configured to hash with Argon2id
+func (suite *AuthServiceTestSuite) argon2Service(rehash bool) *services.AuthService {
+	cfg := &config.Config{JWTSecret: "test-secret", PasswordHashAlgo: services.PasswordHashArgon2id, PasswordRehash: rehash}
+	return services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+}
+
+func (suite *AuthServiceTestSuite) TestRegister_UsesConfiguredHasher() {
+	// Arrange
+	authService := suite.argon2Service(false)
+	suite.mockUserExists(suite.email, false, nil)
+	suite.mockCreateUser(nil)
+	suite.mockPublishUserCreated(nil)
+
+	// Act
+	user, err := authService.Register(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(user.Password, "$argon2id$"))
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_BcryptHashWithArgon2Configured() {
+	// Arrange
+	authService := suite.argon2Service(false)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+
+	// Act
+	token, _, err := authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_Argon2HashWithBcryptConfigured() {
+	// Arrange
+	argonHash, err := services.DefaultArgon2idHasher().Hash(suite.password)
+	suite.Require().NoError(err)
+	user := &models.User{ID: uuid.New(), Email: suite.email, Password: argonHash}
+	suite.mockGetUserByEmail(suite.email, user, nil)
+	suite.mockTouchLastLogin(user.ID, nil)
+
+	// Act
+	token, _, err := suite.authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashesOldAlgorithm() {
+	// Arrange
+	authService := suite.argon2Service(true)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	var storedHash string
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, suite.testUser.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+	}).Return(nil)
+
+	// Act
+	_, user, err := authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(storedHash, "$argon2id$"))
+	suite.Equal(storedHash, user.Password)
+	suite.True(services.DefaultArgon2idHasher().Verify(storedHash, suite.password))
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashFailureDoesNotFailLogin() {
+	// Arrange
+	authService := suite.argon2Service(true)
+	originalHash := suite.testUser.Password
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, suite.testUser.ID, mock.AnythingOfType("string")).Return(errors.New("connection lost"))
+
+	// Act
+	token, user, err := authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(token)
+	suite.Equal(originalHash, user.Password)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_RehashesLowBcryptCost() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", PasswordRehash: true, BcryptCost: bcrypt.MinCost + 2}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	lowCostHash, err := bcrypt.GenerateFromPassword([]byte(suite.password), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(lowCostHash)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+	var storedHash string
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, suite.testUser.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+	}).Return(nil)
+
+	// Act
+	_, user, err := authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	cost, err := bcrypt.Cost([]byte(storedHash))
+	suite.Require().NoError(err)
+	suite.Equal(bcrypt.MinCost+2, cost)
+	suite.Equal(storedHash, user.Password)
+	suite.NoError(bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(suite.password)))
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_NoRehashForCurrentAlgorithm() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "test-secret", PasswordRehash: true}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockTouchLastLogin(suite.testUser.ID, nil)
+
+	// Act
+	_, _, err := authService.Login(suite.ctx, suite.email, suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ===== VERIFY PASSWORD TESTS =====
+
+func (suite *AuthServiceTestSuite) TestVerifyPassword_Success() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+
+	// Act
+	err := suite.authService.VerifyPassword(suite.ctx, suite.testUser.ID.String(), suite.password)
+
+	// Assert
+	suite.Require().NoError(err)
+}
+
+func (suite *AuthServiceTestSuite) TestVerifyPassword_WrongPassword() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+
+	// Act
+	err := suite.authService.VerifyPassword(suite.ctx, suite.testUser.ID.String(), suite.wrongPassword)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+}
+
+func (suite *AuthServiceTestSuite) TestVerifyPassword_UnknownUser() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(nil, errors.New("record not found"))
+
+	// Act
+	err := suite.authService.VerifyPassword(suite.ctx, suite.testUser.ID.String(), suite.password)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+}
+
+func (suite *AuthServiceTestSuite) TestVerifyPassword_InvalidUserID() {
+	// Act
+	err := suite.authService.VerifyPassword(suite.ctx, "not-a-uuid", suite.password)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_InvalidPasswordIsInvalidCredentials() {
+	// Arrange
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 1, nil)
+
+	// Act
+	_, _, err := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidCredentials)
+}
+
+func (suite *AuthServiceTestSuite) TestLogin_UnknownEmailTakesAsLongAsWrongPassword() {
+	// Arrange
+	unknownEmail := "unknown@example.com"
+	suite.mockGetUserByEmail(unknownEmail, nil, errors.New("record not found"))
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockIncrementFailedAttempts(suite.testUser.ID, 1, nil)
+	// Warm up the dummy hash so its one-time generation is not measured
+	suite.authService.Login(suite.ctx, unknownEmail, suite.password)
+
+	// Act
+	start := time.Now()
+	_, _, unknownErr := suite.authService.Login(suite.ctx, unknownEmail, suite.password)
+	unknownElapsed := time.Since(start)
+	start = time.Now()
+	_, _, wrongErr := suite.authService.Login(suite.ctx, suite.email, suite.wrongPassword)
+	wrongElapsed := time.Since(start)
+
+	// Assert
+	suite.Require().ErrorIs(unknownErr, services.ErrInvalidCredentials)
+	suite.Require().ErrorIs(wrongErr, services.ErrInvalidCredentials)
+	suite.GreaterOrEqual(unknownElapsed, wrongElapsed/2)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "IncrementFailedAttempts", 1)
+}
+
+// ===== EXPORT USER DATA TESTS =====
+
+func (suite *AuthServiceTestSuite) TestExportUserData_Success() {
+	// Arrange
+	suite.testUser.Role = models.RoleUser
+	suite.testUser.Profile = models.Profile{DisplayName: "Test User", Locale: "en"}
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(suite.testUser, nil)
+	exportedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	suite.authService.SetClock(func() time.Time { return exportedAt })
+
+	// Act
+	data, err := suite.authService.ExportUserData(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().NoError(err)
+	var export map[string]any
+	suite.Require().NoError(json.Unmarshal(data, &export))
+	suite.Equal("2026-03-01T09:30:00Z", export["exported_at"])
+	user := export["user"].(map[string]any)
+	suite.Equal(suite.testUser.ID.String(), user["id"])
+	suite.Equal(suite.email, user["email"])
+	suite.Equal(models.RoleUser, user["role"])
+	suite.Equal(map[string]any{"display_name": "Test User", "locale": "en"}, user["profile"])
+	suite.NotContains(user, "password")
+	suite.NotContains(string(data), string(suite.hashedPassword))
+}
+
+func (suite *AuthServiceTestSuite) TestExportUserData_MalformedID() {
+	// Act
+	data, err := suite.authService.ExportUserData(suite.ctx, "not-a-uuid")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidArgument)
+	suite.Nil(data)
+}
+
+func (suite *AuthServiceTestSuite) TestExportUserData_UserNotFound() {
+	// Arrange
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(nil, errors.New("record not found"))
+
+	// Act
+	data, err := suite.authService.ExportUserData(suite.ctx, suite.testUser.ID.String())
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "failed to load user")
+	suite.Nil(data)
+}
+
+// ===== PARSE UNVERIFIED CLAIMS TESTS =====
+
+func (suite *AuthServiceTestSuite) TestParseUnverifiedClaims_Success() {
+	// Arrange
+	token, _ := suite.authService.GenerateJWTToken(suite.testUser)
+
+	// Act
+	claims, err := services.ParseUnverifiedClaims(token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, claims.Email)
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+	suite.Require().NotNil(claims.ExpiresAt)
+	suite.True(claims.ExpiresAt.After(time.Now()))
+}
+
+func (suite *AuthServiceTestSuite) TestParseUnverifiedClaims_ExpiredToken() {
+	// Arrange
+	expiresAt := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     expiresAt.Unix(),
+	})
+	expiredToken, _ := token.SignedString(suite.authService.JWTSecret)
+
+	// Act
+	claims, err := services.ParseUnverifiedClaims(expiredToken)
+	_, validateErr := suite.authService.ValidateToken(suite.ctx, expiredToken)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(expiresAt.Equal(claims.ExpiresAt.Time))
+	suite.Require().Error(validateErr)
+	suite.Contains(validateErr.Error(), "token is expired")
+}
+
+func (suite *AuthServiceTestSuite) TestParseUnverifiedClaims_TamperedToken() {
+	// Arrange
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+	})
+	forgedToken, _ := token.SignedString([]byte("wrong-secret-key"))
+
+	// Act
+	claims, err := services.ParseUnverifiedClaims(forgedToken)
+	_, validateErr := suite.authService.ValidateToken(suite.ctx, forgedToken)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.Email, claims.Email)
+	suite.Require().Error(validateErr)
+	suite.Contains(validateErr.Error(), "signature is invalid")
+}
+
+func (suite *AuthServiceTestSuite) TestParseUnverifiedClaims_Malformed() {
+	// Act
+	claims, err := services.ParseUnverifiedClaims("not-a-token")
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Nil(claims)
+	suite.Contains(err.Error(), "failed to decode token")
+}
+
+func (suite *AuthServiceTestSuite) TestNewAuthService_Base64Secret() {
+	// Arrange
+	secret := []byte{0x00, 0xff, 0x10, 0x80, 0x7f, 0x01, 0xfe, 0x42}
+	cfg := &config.Config{JWTSecretBase64: base64.StdEncoding.EncodeToString(secret)}
+
+	// Act
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+	token, err := authService.GenerateJWTToken(suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(secret, authService.JWTSecret)
+	_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return secret, nil })
+	suite.Require().NoError(err)
+}
+
+// ===== KEY ROTATION TESTS =====
+
+func (suite *AuthServiceTestSuite) TestGenerateJWTToken_IncludesKeyID() {
+	// Arrange
+	cfg := &config.Config{JWTSecret: "current-secret", JWTKeyID: "current"}
+	authService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, cfg)
+
+	// Act
+	token, err := authService.GenerateJWTToken(suite.testUser)
+
+	// Assert
+	suite.Require().NoError(err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	suite.Require().NoError(err)
+	suite.Equal("current", parsed.Header["kid"])
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_RetiredKeyStillVerifies() {
+	// Arrange
+	oldService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker,
+		&config.Config{JWTSecret: "old-secret", JWTKeyID: "old"})
+	token, err := oldService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+
+	newService := services.NewAuthService(suite.mockUserRepo, suite.mockMessageBroker, &config.Config{
+		JWTSecret:           "new-secret",
+		JWTKeyID:            "new",
+		JWTVerificationKeys: map[string]string{"old": "old-secret"},
+	})
+	suite.mockSessionsRevokedAt(suite.testUser.ID, nil, nil)
+
+	// Act
+	claims, err := newService.ValidateToken(suite.ctx, token)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(suite.testUser.ID.String(), claims.UserID)
+}
+
+func (suite *AuthServiceTestSuite) TestValidateToken_UnknownKeyID() {
+	// Arrange
+	claims := jwt.MapClaims{
+		"email":   suite.testUser.Email,
+		"user_id": suite.testUser.ID.String(),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "unknown"
+	signed, _ := token.SignedString(suite.authService.JWTSecret)
+
+	// Act
+	validatedClaims, err := suite.authService.ValidateToken(suite.ctx, signed)
+
+	// Assert
+	suite.Require().Error(err)
+	suite.Require().Nil(validatedClaims)
+	suite.Contains(err.Error(), "unknown key id")
+}
+
+// ===== PASSWORD RESET TESTS =====
+
+// mockStoredPassword serves the test user's current password hash from GetUserByID
+// and stores every hash written through UpdatePassword, like the database would
+func (suite *AuthServiceTestSuite) mockStoredPassword() {
+	suite.mockGetUserByEmail(suite.email, suite.testUser, nil)
+	suite.mockUserRepo.On("GetUserByID", mock.Anything, suite.testUser.ID).Return(func(context.Context, uuid.UUID) (*models.User, error) {
+		user := *suite.testUser
+		return &user, nil
+	}).Maybe()
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, suite.testUser.ID, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		suite.testUser.Password = args.String(2)
+	}).Return(nil).Maybe()
+}
+
+func (suite *AuthServiceTestSuite) TestResetPassword_SucceedsOnce() {
+	// Arrange
+	suite.mockStoredPassword()
+	token, err := suite.authService.IssuePasswordResetToken(suite.ctx, suite.email)
+	suite.Require().NoError(err)
+
+	// Act
+	firstErr := suite.authService.ResetPassword(suite.ctx, token, "NewPassword456")
+	secondErr := suite.authService.ResetPassword(suite.ctx, token, "OtherPassword789")
+
+	// Assert
+	suite.Require().NoError(firstErr)
+	suite.Require().NoError(bcrypt.CompareHashAndPassword([]byte(suite.testUser.Password), []byte("NewPassword456")))
+	suite.Require().ErrorIs(secondErr, services.ErrInvalidResetToken)
+	suite.mockUserRepo.AssertNumberOfCalls(suite.T(), "UpdatePassword", 1)
+}
+
+func (suite *AuthServiceTestSuite) TestResetPassword_InvalidatedByPasswordChange() {
+	// Arrange
+	suite.mockStoredPassword()
+	token, err := suite.authService.IssuePasswordResetToken(suite.ctx, suite.email)
+	suite.Require().NoError(err)
+	changedHash, err := bcrypt.GenerateFromPassword([]byte("ChangedElsewhere1"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.testUser.Password = string(changedHash)
+
+	// Act
+	err = suite.authService.ResetPassword(suite.ctx, token, "NewPassword456")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidResetToken)
+	suite.Equal(string(changedHash), suite.testUser.Password)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthServiceTestSuite) TestResetPassword_Expired() {
+	// Arrange
+	suite.mockStoredPassword()
+	issuedAt := time.Now()
+	suite.authService.SetClock(func() time.Time { return issuedAt })
+	token, err := suite.authService.IssuePasswordResetToken(suite.ctx, suite.email)
+	suite.Require().NoError(err)
+	suite.authService.SetClock(func() time.Time { return issuedAt.Add(services.PasswordResetTokenTTL + time.Minute) })
+
+	// Act
+	err = suite.authService.ResetPassword(suite.ctx, token, "NewPassword456")
+
+	// Assert
+	suite.Require().ErrorIs(err, services.ErrInvalidResetToken)
+}
+
+func (suite *AuthServiceTestSuite) TestResetPassword_TokensAreNotInterchangeable() {
+	// Arrange
+	suite.mockStoredPassword()
+	resetToken, err := suite.authService.IssuePasswordResetToken(suite.ctx, suite.email)
+	suite.Require().NoError(err)
+	accessToken, err := suite.authService.GenerateJWTToken(suite.testUser)
+	suite.Require().NoError(err)
+
+	// Act
+	_, validateErr := suite.authService.ValidateToken(suite.ctx, resetToken)
+	resetErr := suite.authService.ResetPassword(suite.ctx, accessToken, "NewPassword456")
+
+	// Assert
+	suite.Require().Error(validateErr, "a reset token must not work as an access token")
+	suite.Require().ErrorIs(resetErr, services.ErrInvalidResetToken)
+}
+
 // Run tests
 func TestAuthServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthServiceTestSuite))
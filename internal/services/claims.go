@@ -0,0 +1,25 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the typed payload of tokens issued by GenerateJWTToken
+type Claims struct {
+	Email  string `json:"email"`
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// ParseUnverifiedClaims decodes token claims WITHOUT checking the signature or expiry
+// It is intended for debugging only, e.g. reading the expiry of a token that no longer validates
+// Never use the result for authentication or authorization; use ValidateToken instead
+func ParseUnverifiedClaims(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %v", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// UserDataExport is the document returned for a Subject Access Request
+type UserDataExport struct {
+	ExportedAt time.Time    `json:"exported_at"`
+	User       ExportedUser `json:"user"`
+}
+
+// ExportedUser lists the stored user fields, credentials are deliberately left out
+type ExportedUser struct {
+	ID          uuid.UUID      `json:"id"`
+	Email       string         `json:"email"`
+	Role        string         `json:"role"`
+	Profile     models.Profile `json:"profile"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	LastLoginAt *time.Time     `json:"last_login_at,omitempty"`
+}
+
+// ExportUserData returns everything stored about a user as a JSON document
+// The password hash is never included, the export itself is logged
+func (s *AuthService) ExportUserData(ctx context.Context, userID string) ([]byte, error) {
+	if s.userRepo == nil {
+		return nil, errors.New("user repository is not initialized")
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed user id", ErrInvalidArgument)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	data, err := json.Marshal(UserDataExport{
+		ExportedAt: s.now().UTC(),
+		User: ExportedUser{
+			ID:          user.ID,
+			Email:       user.Email,
+			Role:        user.Role,
+			Profile:     user.Profile,
+			CreatedAt:   user.CreatedAt,
+			UpdatedAt:   user.UpdatedAt,
+			LastLoginAt: user.LastLoginAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user data: %w", err)
+	}
+
+	slog.InfoContext(ctx, "User data exported", slog.String("user_id", user.ID.String()), slog.Int("bytes", len(data)))
+	return data, nil
+}
@@ -10,8 +10,19 @@ import (
 //go:generate mockery --name=IAuthService --output=./mocks --outpkg=mocks --filename=IAuthService.go
 type IAuthService interface {
 	Register(ctx context.Context, email, password string) (*models.User, error)
+	RegisterWithProfile(ctx context.Context, email, password string, profile *models.Profile) (*models.User, error)
 	Login(ctx context.Context, email, password string) (string, *models.User, error)
-	ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error)
+	VerifyPassword(ctx context.Context, userID, password string) error
+	ExportUserData(ctx context.Context, userID string) ([]byte, error)
+	IssuePasswordResetToken(ctx context.Context, email string) (string, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	RevokeAllSessions(ctx context.Context, userID string) error
+	SuspendUser(ctx context.Context, userID string) error
+	UnsuspendUser(ctx context.Context, userID string) error
+	ResetFailedAttempts(ctx context.Context, userID string) error
+	ValidateToken(ctx context.Context, tokenString string) (*Claims, error)
+	ValidateTokens(ctx context.Context, tokens []string) []TokenResult
+	ValidateTokenRaw(ctx context.Context, tokenString string) (jwt.MapClaims, error)
 	GenerateJWTToken(user *models.User) (string, error)
 }
 
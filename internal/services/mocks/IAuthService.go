@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/Koshsky/subs-service/auth-service/internal/models"
+
+	services "github.com/Koshsky/subs-service/auth-service/internal/services"
 )
 
 // IAuthService is an autogenerated mock type for the IAuthService type
@@ -16,6 +18,36 @@ type IAuthService struct {
 	mock.Mock
 }
 
+// ExportUserData provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) ExportUserData(ctx context.Context, userID string) ([]byte, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateJWTToken provides a mock function with given fields: user
 func (_m *IAuthService) GenerateJWTToken(user *models.User) (string, error) {
 	ret := _m.Called(user)
@@ -44,6 +76,34 @@ func (_m *IAuthService) GenerateJWTToken(user *models.User) (string, error) {
 	return r0, r1
 }
 
+// IssuePasswordResetToken provides a mock function with given fields: ctx, email
+func (_m *IAuthService) IssuePasswordResetToken(ctx context.Context, email string) (string, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssuePasswordResetToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Login provides a mock function with given fields: ctx, email, password
 func (_m *IAuthService) Login(ctx context.Context, email string, password string) (string, *models.User, error) {
 	ret := _m.Called(ctx, email, password)
@@ -111,14 +171,164 @@ func (_m *IAuthService) Register(ctx context.Context, email string, password str
 	return r0, r1
 }
 
+// RegisterWithProfile provides a mock function with given fields: ctx, email, password, profile
+func (_m *IAuthService) RegisterWithProfile(ctx context.Context, email string, password string, profile *models.Profile) (*models.User, error) {
+	ret := _m.Called(ctx, email, password, profile)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterWithProfile")
+	}
+
+	var r0 *models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *models.Profile) (*models.User, error)); ok {
+		return rf(ctx, email, password, profile)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *models.Profile) *models.User); ok {
+		r0 = rf(ctx, email, password, profile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *models.Profile) error); ok {
+		r1 = rf(ctx, email, password, profile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, newPassword
+func (_m *IAuthService) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	ret := _m.Called(ctx, token, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetFailedAttempts provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) ResetFailedAttempts(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetFailedAttempts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllSessions provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) RevokeAllSessions(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SuspendUser provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) SuspendUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuspendUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsuspendUser provides a mock function with given fields: ctx, userID
+func (_m *IAuthService) UnsuspendUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsuspendUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateToken provides a mock function with given fields: ctx, tokenString
-func (_m *IAuthService) ValidateToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+func (_m *IAuthService) ValidateToken(ctx context.Context, tokenString string) (*services.Claims, error) {
 	ret := _m.Called(ctx, tokenString)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ValidateToken")
 	}
 
+	var r0 *services.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*services.Claims, error)); ok {
+		return rf(ctx, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *services.Claims); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*services.Claims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateTokenRaw provides a mock function with given fields: ctx, tokenString
+func (_m *IAuthService) ValidateTokenRaw(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateTokenRaw")
+	}
+
 	var r0 jwt.MapClaims
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) (jwt.MapClaims, error)); ok {
@@ -141,6 +351,44 @@ func (_m *IAuthService) ValidateToken(ctx context.Context, tokenString string) (
 	return r0, r1
 }
 
+// ValidateTokens provides a mock function with given fields: ctx, tokens
+func (_m *IAuthService) ValidateTokens(ctx context.Context, tokens []string) []services.TokenResult {
+	ret := _m.Called(ctx, tokens)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateTokens")
+	}
+
+	var r0 []services.TokenResult
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []services.TokenResult); ok {
+		r0 = rf(ctx, tokens)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]services.TokenResult)
+		}
+	}
+
+	return r0
+}
+
+// VerifyPassword provides a mock function with given fields: ctx, userID, password
+func (_m *IAuthService) VerifyPassword(ctx context.Context, userID string, password string) error {
+	ret := _m.Called(ctx, userID, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIAuthService creates a new instance of IAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIAuthService(t interface {
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// PasswordHasher hashes passwords into self-describing encoded strings
+// The algorithm is identified by the hash prefix, so hashes of different algorithms can coexist
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password
+	Hash(password string) (string, error)
+	// Verify reports whether password matches an encoded hash produced by this algorithm
+	Verify(encodedHash, password string) bool
+	// Owns reports whether encodedHash was produced by this algorithm
+	Owns(encodedHash string) bool
+	// NeedsRehash reports whether encodedHash should be replaced by a hash from this hasher,
+	// because it uses another algorithm or weaker parameters
+	NeedsRehash(encodedHash string) bool
+}
+
+// NewPasswordHasher returns the hasher for algo, defaulting to bcrypt when algo is empty
+// bcryptCost applies to bcrypt, zero selects bcrypt.DefaultCost
+func NewPasswordHasher(algo string, bcryptCost int) (PasswordHasher, error) {
+	switch algo {
+	case "", PasswordHashBcrypt:
+		if bcryptCost == 0 {
+			bcryptCost = bcrypt.DefaultCost
+		}
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost %d is outside %d..%d", bcryptCost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return BcryptHasher{Cost: bcryptCost}, nil
+	case PasswordHashArgon2id:
+		return DefaultArgon2idHasher(), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", algo)
+	}
+}
+
+// knownHashers lists every algorithm stored hashes may use, for verification
+var knownHashers = []PasswordHasher{
+	BcryptHasher{Cost: bcrypt.DefaultCost},
+	DefaultArgon2idHasher(),
+}
+
+// BcryptHasher hashes passwords with bcrypt, encoded as $2a$...
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h BcryptHasher) Verify(encodedHash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password)) == nil
+}
+
+func (h BcryptHasher) Owns(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2")
+}
+
+// NeedsRehash is true for hashes of other algorithms and bcrypt hashes below the configured cost
+func (h BcryptHasher) NeedsRehash(encodedHash string) bool {
+	if !h.Owns(encodedHash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err == nil && cost < h.Cost
+}
+
+// argon2idPrefix starts every Argon2id hash in PHC string format
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idHasher hashes passwords with Argon2id, encoded in PHC string format:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32 // in KiB
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// DefaultArgon2idHasher returns an Argon2id hasher with the OWASP recommended parameters
+func DefaultArgon2idHasher() Argon2idHasher {
+	return Argon2idHasher{Time: 2, Memory: 19 * 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %v", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify uses the parameters stored in the hash, so hashes made with older settings keep verifying
+func (h Argon2idHasher) Verify(encodedHash, password string) bool {
+	params, salt, key, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return false
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, candidate) == 1
+}
+
+func (h Argon2idHasher) Owns(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, argon2idPrefix)
+}
+
+// NeedsRehash is true for hashes of other algorithms only, Argon2id parameters are not compared
+func (h Argon2idHasher) NeedsRehash(encodedHash string) bool {
+	return !h.Owns(encodedHash)
+}
+
+// decodeArgon2idHash parses a PHC string into its parameters, salt and key
+func decodeArgon2idHash(encodedHash string) (Argon2idHasher, []byte, []byte, error) {
+	var params Argon2idHasher
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errors.New("malformed argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errors.New("malformed argon2id key")
+	}
+	return params, salt, key, nil
+}
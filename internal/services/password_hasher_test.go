@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		algo   string
+		prefix string
+	}{
+		{name: "Default is bcrypt", algo: "", prefix: "$2a$"},
+		{name: "Bcrypt", algo: services.PasswordHashBcrypt, prefix: "$2a$"},
+		{name: "Argon2id", algo: services.PasswordHashArgon2id, prefix: "$argon2id$v=19$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := services.NewPasswordHasher(tt.algo, 0)
+			require.NoError(t, err)
+
+			hash, err := hasher.Hash("Password123!")
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+			assert.True(t, hasher.Owns(hash))
+			assert.True(t, hasher.Verify(hash, "Password123!"))
+			assert.False(t, hasher.Verify(hash, "Password123?"))
+		})
+	}
+}
+
+func TestPasswordHasher_UnsupportedAlgorithm(t *testing.T) {
+	hasher, err := services.NewPasswordHasher("md5", 0)
+
+	require.Error(t, err)
+	assert.Nil(t, hasher)
+	assert.Contains(t, err.Error(), "unsupported password hash algorithm")
+}
+
+func TestPasswordHasher_InvalidBcryptCost(t *testing.T) {
+	hasher, err := services.NewPasswordHasher(services.PasswordHashBcrypt, bcrypt.MaxCost+1)
+
+	require.Error(t, err)
+	assert.Nil(t, hasher)
+	assert.Contains(t, err.Error(), "bcrypt cost")
+}
+
+func TestBcryptHasher_NeedsRehash(t *testing.T) {
+	lowCost, err := bcrypt.GenerateFromPassword([]byte("Password123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	argonHash, err := services.DefaultArgon2idHasher().Hash("Password123!")
+	require.NoError(t, err)
+
+	assert.True(t, services.BcryptHasher{Cost: bcrypt.MinCost + 1}.NeedsRehash(string(lowCost)))
+	assert.False(t, services.BcryptHasher{Cost: bcrypt.MinCost}.NeedsRehash(string(lowCost)))
+	assert.True(t, services.BcryptHasher{Cost: bcrypt.MinCost}.NeedsRehash(argonHash))
+	assert.False(t, services.DefaultArgon2idHasher().NeedsRehash(argonHash))
+	assert.True(t, services.DefaultArgon2idHasher().NeedsRehash(string(lowCost)))
+}
+
+func TestArgon2idHasher_LongPasswords(t *testing.T) {
+	hasher := services.DefaultArgon2idHasher()
+	password := strings.Repeat("a", 100)
+
+	hash, err := hasher.Hash(password)
+
+	require.NoError(t, err)
+	assert.True(t, hasher.Verify(hash, password))
+	assert.False(t, hasher.Verify(hash, strings.Repeat("a", 99)))
+}
+
+func TestArgon2idHasher_VerifiesWithStoredParameters(t *testing.T) {
+	weak := services.Argon2idHasher{Time: 1, Memory: 8 * 1024, Threads: 2, KeyLen: 16, SaltLen: 8}
+	hash, err := weak.Hash("Password123!")
+	require.NoError(t, err)
+
+	assert.True(t, services.DefaultArgon2idHasher().Verify(hash, "Password123!"))
+}
+
+func TestArgon2idHasher_RejectsMalformedHashes(t *testing.T) {
+	hasher := services.DefaultArgon2idHasher()
+	valid, err := hasher.Hash("Password123!")
+	require.NoError(t, err)
+	parts := strings.Split(valid, "$")
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "Empty", hash: ""},
+		{name: "Wrong segment count", hash: "$argon2id$v=19$m=19456,t=2,p=1$salt"},
+		{name: "Wrong version", hash: strings.Join([]string{"", "argon2id", "v=16", parts[3], parts[4], parts[5]}, "$")},
+		{name: "Bad parameters", hash: strings.Join([]string{"", "argon2id", parts[2], "m=x", parts[4], parts[5]}, "$")},
+		{name: "Bad salt", hash: strings.Join([]string{"", "argon2id", parts[2], parts[3], "!!", parts[5]}, "$")},
+		{name: "Bcrypt hash", hash: "$2a$10$abcdefghijklmnopqrstuuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, hasher.Verify(tt.hash, "Password123!"))
+		})
+	}
+}
+
+func TestPasswordHasher_Owns(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("Password123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	argonHash, err := services.DefaultArgon2idHasher().Hash("Password123!")
+	require.NoError(t, err)
+
+	bcryptHasher := services.BcryptHasher{Cost: bcrypt.MinCost}
+	argonHasher := services.DefaultArgon2idHasher()
+
+	assert.True(t, bcryptHasher.Owns(string(bcryptHash)))
+	assert.False(t, bcryptHasher.Owns(argonHash))
+	assert.True(t, argonHasher.Owns(argonHash))
+	assert.False(t, argonHasher.Owns(string(bcryptHash)))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/metrics"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenTypePasswordReset labels password reset tokens in issuance logs and metrics
+const TokenTypePasswordReset = "password_reset"
+
+// PasswordResetTokenTTL is how long a password reset token stays valid
+const PasswordResetTokenTTL = time.Hour
+
+// ErrInvalidResetToken is returned when a reset token is malformed, expired or already used
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// passwordResetKeyLabel separates the reset signing key from the access token key
+const passwordResetKeyLabel = "password-reset"
+
+// passwordResetClaims is the payload of tokens issued by IssuePasswordResetToken
+// Nonce is derived from the password hash current at issuance, see passwordResetNonce
+type passwordResetClaims struct {
+	UserID string `json:"user_id"`
+	Nonce  string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// IssuePasswordResetToken returns a single-use token that lets the holder set a new password
+// The token is tied to the current password hash, so it stops working as soon as the password
+// changes, whether through ResetPassword or any other path
+func (s *AuthService) IssuePasswordResetToken(ctx context.Context, email string) (string, error) {
+	if s.userRepo == nil {
+		return "", errors.New("user repository is not initialized")
+	}
+	if s.JWTSecret == nil {
+		return "", errors.New("JWT secret is not configured")
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	now := s.now()
+	claims := passwordResetClaims{
+		UserID: user.ID.String(),
+		Nonce:  s.passwordResetNonce(user.Password),
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(PasswordResetTokenTTL)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.passwordResetKey())
+	if err != nil {
+		return "", err
+	}
+
+	metrics.TokensIssued.WithLabelValues(TokenTypePasswordReset).Inc()
+	slog.InfoContext(ctx, "Token issued", slog.String("user_id", user.ID.String()), slog.String("token_type", TokenTypePasswordReset))
+	return signed, nil
+}
+
+// ResetPassword sets a new password for the user named by a token from IssuePasswordResetToken
+// Tokens that are expired, were issued before the last password change or were already used
+// are rejected with ErrInvalidResetToken
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.userRepo == nil {
+		return errors.New("user repository is not initialized")
+	}
+	if s.JWTSecret == nil {
+		return errors.New("JWT secret is not configured")
+	}
+	if err := s.validateCredentialLengths("", newPassword); err != nil {
+		return err
+	}
+
+	claims := &passwordResetClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.passwordResetKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithTimeFunc(s.now))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResetToken, err)
+	}
+
+	id, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid token subject", ErrInvalidResetToken)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !hmac.Equal([]byte(claims.Nonce), []byte(s.passwordResetNonce(user.Password))) {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Password reset", slog.String("user_id", user.ID.String()))
+	return nil
+}
+
+// passwordResetKey derives the key reset tokens are signed with from the JWT secret
+// A separate key keeps reset tokens from validating as access tokens and the other way round
+func (s *AuthService) passwordResetKey() []byte {
+	mac := hmac.New(sha256.New, s.JWTSecret)
+	mac.Write([]byte(passwordResetKeyLabel))
+	return mac.Sum(nil)
+}
+
+// passwordResetNonce derives the reset token nonce from a password hash
+// It is keyed so the token payload reveals nothing about the hash
+func (s *AuthService) passwordResetNonce(passwordHash string) string {
+	mac := hmac.New(sha256.New, s.passwordResetKey())
+	mac.Write([]byte(passwordHash))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/Koshsky/subs-service/auth-service/internal/models"
+	"github.com/Koshsky/subs-service/auth-service/internal/utils"
+)
+
+// MaxDisplayNameLength is the maximum display name length in characters
+const MaxDisplayNameLength = 100
+
+// validateProfile checks the optional profile fields, empty fields are skipped
+func validateProfile(profile *models.Profile) error {
+	if utf8.RuneCountInString(profile.DisplayName) > MaxDisplayNameLength {
+		return fmt.Errorf("display name must be at most %d characters", MaxDisplayNameLength)
+	}
+	if profile.Locale != "" {
+		if err := utils.ValidateLocale(profile.Locale); err != nil {
+			return err
+		}
+	}
+	if profile.Timezone != "" {
+		if err := utils.ValidateTimezone(profile.Timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyProfileDefaults fills the locale and timezone left empty at registration
+func (s *AuthService) applyProfileDefaults(profile *models.Profile) {
+	if profile.Locale == "" {
+		profile.Locale = s.defaultLocale
+	}
+	if profile.Timezone == "" {
+		profile.Timezone = s.defaultTimezone
+	}
+}
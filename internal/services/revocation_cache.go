@@ -0,0 +1,50 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// revocationCacheTTL bounds how long a looked-up revocation time is reused
+// A revocation made through another instance takes effect here within this delay
+const revocationCacheTTL = 5 * time.Second
+
+// revocationCache keeps the last session revocation time per user for revocationCacheTTL,
+// so validating a token does not query the database on every request
+// The zero value is ready to use and safe for concurrent use
+type revocationCache struct {
+	mu      sync.Mutex
+	entries map[string]revocationEntry
+}
+
+// revocationEntry is a cached revocation time, nil when the user never revoked sessions
+type revocationEntry struct {
+	revokedAt *time.Time
+	expiresAt time.Time
+}
+
+// get returns the cached revocation time of userID and whether it is still fresh at now
+func (c *revocationCache) get(userID string, now time.Time) (*time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.revokedAt, true
+}
+
+// put caches the revocation time of userID from now on
+func (c *revocationCache) put(userID string, revokedAt *time.Time, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]revocationEntry)
+	}
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = revocationEntry{revokedAt: revokedAt, expiresAt: now.Add(revocationCacheTTL)}
+}
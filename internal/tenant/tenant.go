@@ -0,0 +1,45 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DefaultSchema is the schema used when a request carries no tenant
+const DefaultSchema = "public"
+
+// MetadataKey is the incoming gRPC metadata key holding the tenant id
+const MetadataKey = "x-tenant-id"
+
+// idPattern restricts tenant ids to lowercase Postgres identifiers, so they are safe as schema names
+var idPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+type tenantKey struct{}
+
+// ValidateID checks that id can be used as a schema name
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid tenant id %q", id)
+	}
+	return nil
+}
+
+// WithID returns a copy of ctx carrying the tenant id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant id stored in ctx
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Schema returns the schema holding the tenant's tables, DefaultSchema when ctx has no tenant
+func Schema(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return DefaultSchema
+}
@@ -0,0 +1,40 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	assert.Equal(t, DefaultSchema, Schema(context.Background()))
+	assert.Equal(t, DefaultSchema, Schema(WithID(context.Background(), "")))
+	assert.Equal(t, "acme", Schema(WithID(context.Background(), "acme")))
+}
+
+func TestValidateID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{id: "acme", valid: true},
+		{id: "tenant_42", valid: true},
+		{id: "", valid: false},
+		{id: "42tenant", valid: false},
+		{id: "Acme", valid: false},
+		{id: "acme.users", valid: false},
+		{id: `acme"; DROP TABLE users; --`, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			err := ValidateID(tt.id)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // GetEnv gets an environment variable with default value
@@ -33,6 +36,15 @@ func GetEnvRequiredWithValidation(key string, validator func(string) error) stri
 	return value
 }
 
+// GetEnvWithValidation gets an optional environment variable and panics if a set value is invalid
+func GetEnvWithValidation(key, defaultValue string, validator func(string) error) string {
+	value := GetEnv(key, defaultValue)
+	if err := validator(value); err != nil {
+		panic(fmt.Sprintf("CRITICAL ERROR: Environment variable %s validation failed: %v", key, err))
+	}
+	return value
+}
+
 // GetEnvBool gets an environment variable as a boolean
 func GetEnvBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
@@ -81,6 +93,54 @@ func GetEnvIntRequired(key string) int {
 	panic(fmt.Sprintf("CRITICAL ERROR: Environment variable %s is not set", key))
 }
 
+// GetEnvDuration gets an environment variable as a time.Duration (e.g. "500ms", "2s")
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		durationValue, err := time.ParseDuration(value)
+		if err != nil {
+			return defaultValue
+		}
+		return durationValue
+	}
+	return defaultValue
+}
+
+// GetEnvStringSlice gets a comma-separated environment variable as a slice
+// Entries are trimmed and empty entries are skipped
+func GetEnvStringSlice(key string, defaultValue []string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// GetEnvMap gets a comma-separated list of key=value pairs as a map (e.g. "a=true,b=false")
+// Keys and values are trimmed, a value may contain "=", entries without "=" or with an empty key are skipped
+func GetEnvMap(key string, defaultValue map[string]string) map[string]string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	result := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(item, "=")
+		if k = strings.TrimSpace(k); !ok || k == "" {
+			continue
+		}
+		result[k] = strings.TrimSpace(v)
+	}
+	return result
+}
+
 // ValidatePort validates that a string is a valid port number
 func ValidatePort(port string) error {
 	if port == "" {
@@ -107,6 +167,18 @@ func ValidateNonEmpty(value string) error {
 	return nil
 }
 
+// ValidateOneOf validates that a string is one of the allowed values
+func ValidateOneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %q must be one of: %s", value, strings.Join(allowed, ", "))
+	}
+}
+
 // ValidateMinLength validates that a string meets minimum length requirement
 func ValidateMinLength(minLength int) func(string) error {
 	return func(value string) error {
@@ -116,3 +188,110 @@ func ValidateMinLength(minLength int) func(string) error {
 		return nil
 	}
 }
+
+// ValidateIntRange validates that a string is an integer between min and max inclusive
+func ValidateIntRange(min, max int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("value %q must be an integer", value)
+		}
+		if n < min || n > max {
+			return fmt.Errorf("value %d must be between %d and %d", n, min, max)
+		}
+		return nil
+	}
+}
+
+// ValidateFloatRange validates that a string is a number between min and max inclusive
+func ValidateFloatRange(min, max float64) func(string) error {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(f) {
+			return fmt.Errorf("value %q must be a number", value)
+		}
+		if f < min || f > max {
+			return fmt.Errorf("value %g must be between %g and %g", f, min, max)
+		}
+		return nil
+	}
+}
+
+// ValidateMinEntropyBits validates that a string carries at least minBits of Shannon entropy
+// The estimate is the per-character entropy of the value multiplied by its length
+func ValidateMinEntropyBits(minBits float64) func(string) error {
+	return func(value string) error {
+		if bits := shannonEntropyBits(value); bits < minBits {
+			return fmt.Errorf("value entropy is too low: %.0f bits, need at least %.0f", bits, minBits)
+		}
+		return nil
+	}
+}
+
+// ValidateMinKeyEntropyBits validates that a binary key carries at least minBits of estimated entropy
+// The estimate counts 4-bit symbols, so a random key is not penalised for the few repeated bytes it has
+func ValidateMinKeyEntropyBits(minBits float64) func([]byte) error {
+	return func(key []byte) error {
+		if bits := keyEntropyBits(key); bits < minBits {
+			return fmt.Errorf("key entropy is too low: %.0f bits, need at least %.0f", bits, minBits)
+		}
+		return nil
+	}
+}
+
+// ValidateAll combines validators, returning the first error encountered
+func ValidateAll(validators ...func(string) error) func(string) error {
+	return func(value string) error {
+		for _, validator := range validators {
+			if err := validator(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// shannonEntropyBits estimates the total entropy of a string in bits
+func shannonEntropyBits(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, char := range value {
+		counts[char]++
+		total++
+	}
+
+	perChar := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+	return perChar * float64(total)
+}
+
+// keyEntropyBits estimates the total entropy of a binary key in bits, counting each byte as two 4-bit symbols
+func keyEntropyBits(key []byte) float64 {
+	if len(key) == 0 {
+		return 0
+	}
+
+	var counts [16]int
+	for _, b := range key {
+		counts[b>>4]++
+		counts[b&0x0f]++
+	}
+
+	total := float64(2 * len(key))
+	perSymbol := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		perSymbol -= p * math.Log2(p)
+	}
+	return perSymbol * total
+}
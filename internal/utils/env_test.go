@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/base64"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -177,6 +181,54 @@ func TestGetEnvRequiredWithValidation(t *testing.T) {
 	}
 }
 
+func TestGetEnvWithValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		setValue    string
+		shouldPanic bool
+		expected    string
+	}{
+		{
+			name:     "Unset uses default",
+			key:      "UNSET_VALIDATED_VAR",
+			expected: "bcrypt",
+		},
+		{
+			name:     "Valid value",
+			key:      "VALID_VALIDATED_VAR",
+			setValue: "argon2id",
+			expected: "argon2id",
+		},
+		{
+			name:        "Invalid value",
+			key:         "INVALID_VALIDATED_VAR",
+			setValue:    "md5",
+			shouldPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clean up after test
+			defer os.Unsetenv(tt.key)
+
+			if tt.setValue != "" {
+				os.Setenv(tt.key, tt.setValue)
+			}
+
+			validator := ValidateOneOf("bcrypt", "argon2id")
+			if tt.shouldPanic {
+				assert.Panics(t, func() {
+					GetEnvWithValidation(tt.key, "bcrypt", validator)
+				})
+			} else {
+				assert.Equal(t, tt.expected, GetEnvWithValidation(tt.key, "bcrypt", validator))
+			}
+		})
+	}
+}
+
 func TestGetEnvBool(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -422,6 +474,162 @@ func TestGetEnvIntRequired(t *testing.T) {
 	}
 }
 
+func TestGetEnvDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue time.Duration
+		setValue     string
+		expected     time.Duration
+	}{
+		{
+			name:         "Valid duration",
+			key:          "DURATION_VALID",
+			defaultValue: time.Second,
+			setValue:     "250ms",
+			expected:     250 * time.Millisecond,
+		},
+		{
+			name:         "Invalid duration",
+			key:          "DURATION_INVALID",
+			defaultValue: time.Second,
+			setValue:     "soon",
+			expected:     time.Second, // Should return default
+		},
+		{
+			name:         "Variable does not exist",
+			key:          "NONEXISTENT_DURATION",
+			defaultValue: 2 * time.Second,
+			setValue:     "",
+			expected:     2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clean up after test
+			defer os.Unsetenv(tt.key)
+
+			if tt.setValue != "" {
+				os.Setenv(tt.key, tt.setValue)
+			}
+
+			result := GetEnvDuration(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetEnvStringSlice(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue []string
+		setValue     *string
+		expected     []string
+	}{
+		{
+			name:         "Comma separated values",
+			key:          "SLICE_VALID",
+			defaultValue: nil,
+			setValue:     stringPtr("a, b ,c"),
+			expected:     []string{"a", "b", "c"},
+		},
+		{
+			name:         "Empty entries are skipped",
+			key:          "SLICE_EMPTY_ENTRIES",
+			defaultValue: nil,
+			setValue:     stringPtr("a,,b,"),
+			expected:     []string{"a", "b"},
+		},
+		{
+			name:         "Empty value",
+			key:          "SLICE_EMPTY",
+			defaultValue: []string{"default"},
+			setValue:     stringPtr(""),
+			expected:     []string{},
+		},
+		{
+			name:         "Variable does not exist",
+			key:          "NONEXISTENT_SLICE",
+			defaultValue: []string{"default"},
+			setValue:     nil,
+			expected:     []string{"default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clean up after test
+			defer os.Unsetenv(tt.key)
+
+			if tt.setValue != nil {
+				os.Setenv(tt.key, *tt.setValue)
+			}
+
+			result := GetEnvStringSlice(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetEnvMap(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue map[string]string
+		setValue     *string
+		expected     map[string]string
+	}{
+		{
+			name:         "Key value pairs",
+			key:          "MAP_VALID",
+			defaultValue: nil,
+			setValue:     stringPtr("a=true, b = false ,c=x=y"),
+			expected:     map[string]string{"a": "true", "b": "false", "c": "x=y"},
+		},
+		{
+			name:         "Malformed entries are skipped",
+			key:          "MAP_MALFORMED",
+			defaultValue: nil,
+			setValue:     stringPtr("a=1,missing,=2,,b="),
+			expected:     map[string]string{"a": "1", "b": ""},
+		},
+		{
+			name:         "Empty value",
+			key:          "MAP_EMPTY",
+			defaultValue: map[string]string{"default": "1"},
+			setValue:     stringPtr(""),
+			expected:     map[string]string{},
+		},
+		{
+			name:         "Variable does not exist",
+			key:          "NONEXISTENT_MAP",
+			defaultValue: map[string]string{"default": "1"},
+			setValue:     nil,
+			expected:     map[string]string{"default": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Clean up after test
+			defer os.Unsetenv(tt.key)
+
+			if tt.setValue != nil {
+				os.Setenv(tt.key, *tt.setValue)
+			}
+
+			result := GetEnvMap(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
 func TestValidatePort(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -517,6 +725,15 @@ func TestValidateNonEmpty(t *testing.T) {
 	}
 }
 
+func TestValidateOneOf(t *testing.T) {
+	validator := ValidateOneOf("a", "b")
+
+	assert.NoError(t, validator("a"))
+	assert.NoError(t, validator("b"))
+	assert.Error(t, validator("c"))
+	assert.Error(t, validator(""))
+}
+
 func TestValidateMinLength(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -574,3 +791,179 @@ func TestValidateMinLength(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIntRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "Lower bound",
+			value:       "4",
+			expectError: false,
+		},
+		{
+			name:        "Upper bound",
+			value:       "31",
+			expectError: false,
+		},
+		{
+			name:        "Within range",
+			value:       "12",
+			expectError: false,
+		},
+		{
+			name:          "Below range",
+			value:         "3",
+			expectError:   true,
+			errorContains: "value 3 must be between 4 and 31",
+		},
+		{
+			name:          "Above range",
+			value:         "32",
+			expectError:   true,
+			errorContains: "value 32 must be between 4 and 31",
+		},
+		{
+			name:          "Non-numeric",
+			value:         "twelve",
+			expectError:   true,
+			errorContains: `value "twelve" must be an integer`,
+		},
+		{
+			name:          "Empty string",
+			value:         "",
+			expectError:   true,
+			errorContains: "must be an integer",
+		},
+	}
+
+	validator := ValidateIntRange(4, 31)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator(tt.value)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateFloatRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expectError   bool
+		errorContains string
+	}{
+		{name: "Lower bound", value: "0"},
+		{name: "Upper bound", value: "1"},
+		{name: "Within range", value: " 0.25 "},
+		{name: "Below range", value: "-0.1", expectError: true, errorContains: "value -0.1 must be between 0 and 1"},
+		{name: "Above range", value: "1.5", expectError: true, errorContains: "value 1.5 must be between 0 and 1"},
+		{name: "Non-numeric", value: "half", expectError: true, errorContains: `value "half" must be a number`},
+		{name: "NaN", value: "NaN", expectError: true, errorContains: "must be a number"},
+	}
+
+	validator := ValidateFloatRange(0, 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator(tt.value)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateMinEntropyBits(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+	}{
+		{
+			name:        "Weak dictionary secret",
+			value:       "password123456789012345678901234",
+			expectError: true,
+		},
+		{
+			name:        "Repeated character",
+			value:       strings.Repeat("a", 64),
+			expectError: true,
+		},
+		{
+			name:        "Empty string",
+			value:       "",
+			expectError: true,
+		},
+		{
+			name:        "Random 32-byte base64",
+			value:       "jspoBKPTOgw+T1nJJ/XlNEoW+LONfXuY/WWCaPcTalc=",
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := ValidateMinEntropyBits(160)
+			err := validator(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateMinKeyEntropyBits(t *testing.T) {
+	random, _ := base64.StdEncoding.DecodeString("jspoBKPTOgw+T1nJJ/XlNEoW+LONfXuY/WWCaPcTalc=")
+
+	tests := []struct {
+		name        string
+		key         []byte
+		expectError bool
+	}{
+		{name: "Zero bytes", key: make([]byte, 32), expectError: true},
+		{name: "Repeated pattern", key: bytes.Repeat([]byte{1, 2, 3, 4}, 8), expectError: true},
+		{name: "Empty key", key: nil, expectError: true},
+		{name: "Random 32 bytes", key: random, expectError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := ValidateMinKeyEntropyBits(160)
+			err := validator(tt.key)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	validator := ValidateAll(ValidateNonEmpty, ValidateMinLength(5))
+
+	assert.NoError(t, validator("hello"))
+
+	err := validator("")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "value cannot be empty")
+
+	err = validator("hi")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 5 characters")
+}
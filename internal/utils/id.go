@@ -0,0 +1,14 @@
+package utils
+
+import "github.com/google/uuid"
+
+// NewUUID returns a time-ordered UUIDv7, so ids sort by creation time
+// It panics only if the system random source fails, like uuid.New
+func NewUUID() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// NewID returns a URL-safe, time-ordered id for requests, events and other correlation ids
+func NewID() string {
+	return NewUUID().String()
+}
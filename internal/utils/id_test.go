@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewID_Unique(t *testing.T) {
+	const count = 10000
+	seen := make(map[string]struct{}, count)
+
+	for i := 0; i < count; i++ {
+		id := NewID()
+		_, duplicate := seen[id]
+		require.False(t, duplicate, "duplicate id %s", id)
+		seen[id] = struct{}{}
+	}
+}
+
+func TestNewID_TimeOrdered(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = NewID()
+	}
+
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	first, err := uuid.Parse(ids[0])
+	require.NoError(t, err)
+	last, err := uuid.Parse(ids[len(ids)-1])
+	require.NoError(t, err)
+	assert.LessOrEqual(t, first.Time(), last.Time())
+}
+
+func TestNewID_Format(t *testing.T) {
+	before := time.Now().Add(-time.Second)
+
+	id := NewID()
+
+	parsed, err := uuid.Parse(id)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+	assert.Equal(t, id, url.PathEscape(id))
+	sec, nsec := parsed.Time().UnixTime()
+	assert.WithinDuration(t, time.Now(), time.Unix(sec, nsec), time.Since(before))
+}
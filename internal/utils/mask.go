@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// emailMaskVisible holds how many leading local-part characters MaskEmail reveals
+var emailMaskVisible atomic.Int64
+
+func init() {
+	emailMaskVisible.Store(1)
+}
+
+// DefaultRedactionPlaceholder replaces redacted values until SetRedactionPlaceholder changes it
+const DefaultRedactionPlaceholder = "[REDACTED]"
+
+// redactionPlaceholder holds the text emitted in place of redacted values
+var redactionPlaceholder atomic.Pointer[string]
+
+// SetRedactionPlaceholder sets the text emitted in place of redacted values
+// by MaskStruct, the log handler and the database query logger, an empty string restores DefaultRedactionPlaceholder
+func SetRedactionPlaceholder(placeholder string) {
+	if placeholder == "" {
+		redactionPlaceholder.Store(nil)
+		return
+	}
+	redactionPlaceholder.Store(&placeholder)
+}
+
+// RedactionPlaceholder returns the text emitted in place of redacted values
+func RedactionPlaceholder() string {
+	if placeholder := redactionPlaceholder.Load(); placeholder != nil {
+		return *placeholder
+	}
+	return DefaultRedactionPlaceholder
+}
+
+// malformedEmails counts the values MaskEmail could not parse as an email address
+var malformedEmails atomic.Int64
+
+// MalformedEmailCount returns how many values MaskEmail could not parse as an email address
+// A growing count means something upstream passes non-email values to logging
+func MalformedEmailCount() int64 {
+	return malformedEmails.Load()
+}
+
+// SetEmailMaskVisible sets how many leading local-part characters MaskEmail reveals
+// Negative values are treated as zero
+func SetEmailMaskVisible(n int) {
+	if n < 0 {
+		n = 0
+	}
+	emailMaskVisible.Store(int64(n))
+}
+
+// MaskEmail masks the local part of an email address for safe logging
+// At most half of the local part is ever revealed, so very short local parts stay hidden
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		malformedEmails.Add(1)
+		return "***"
+	}
+
+	local := []rune(email[:at])
+	visible := int(emailMaskVisible.Load())
+	if limit := len(local) / 2; visible > limit {
+		visible = limit
+	}
+
+	return string(local[:visible]) + "***" + email[at:]
+}
+
+// ipv6MaskBits is the IPv6 prefix MaskIP keeps, a /48 usually identifies a site rather than a host
+const ipv6MaskBits = 48
+
+// MaskIP masks the host part of an IP address or ip:port pair for safe logging
+// IPv4 addresses keep their first three octets and IPv6 addresses their /48 prefix, a port is kept as-is
+// Values that are not an IP address are hidden entirely
+func MaskIP(value string) string {
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		masked := maskAddr(addrPort.Addr())
+		if addrPort.Addr().Is6() {
+			masked = "[" + masked + "]"
+		}
+		return masked + ":" + strconv.Itoa(int(addrPort.Port()))
+	}
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return maskAddr(addr)
+	}
+	return "***"
+}
+
+// maskAddr replaces the host bits of addr with ***
+func maskAddr(addr netip.Addr) string {
+	addr = addr.Unmap()
+	if addr.Is4() {
+		octets := addr.As4()
+		return strconv.Itoa(int(octets[0])) + "." + strconv.Itoa(int(octets[1])) + "." + strconv.Itoa(int(octets[2])) + ".***"
+	}
+	prefix, _ := addr.WithZone("").Prefix(ipv6MaskBits)
+	return strings.TrimSuffix(prefix.Addr().String(), "::") + "::***"
+}
+
+// jwtPattern matches compact JWS tokens: base64url header, payload and signature
+// JSON headers always start with {" which encodes to eyJ
+var jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// jwtSignaturePrefix is how many signature characters MaskJWT keeps for correlation
+const jwtSignaturePrefix = 6
+
+// MaskJWT masks every JWT found in value, keeping the header and a short signature prefix
+// so log lines about the same token can be correlated without exposing claims or the full signature
+// Values without a JWT are returned unchanged
+func MaskJWT(value string) string {
+	if !strings.Contains(value, "eyJ") {
+		return value
+	}
+	return jwtPattern.ReplaceAllStringFunc(value, func(token string) string {
+		parts := strings.SplitN(token, ".", 3)
+		signature := parts[2]
+		if len(signature) > jwtSignaturePrefix {
+			signature = signature[:jwtSignaturePrefix]
+		}
+		return parts[0] + ".***." + signature + "***"
+	})
+}
+
+// MaskToken masks a value known to hold a credential
+// JWTs are masked as in MaskJWT, any other non-empty value is hidden entirely
+func MaskToken(value string) string {
+	if value == "" {
+		return ""
+	}
+	if masked := MaskJWT(value); masked != value {
+		return masked
+	}
+	return "***"
+}
+
+// maskStructMaxDepth bounds MaskStruct recursion so self-referencing values cannot loop forever
+const maskStructMaxDepth = 8
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// MaskStruct converts a struct, or a pointer to one, into a map that is safe to log
+// Exported fields are keyed by their json name, falling back to the field name, and fields
+// tagged json:"-" are skipped. Fields tagged mask:"redact" are replaced with RedactionPlaceholder and fields
+// tagged mask:"email" are masked with MaskEmail. Nested structs become nested maps, unless they
+// marshal themselves like time.Time. Returns nil for nil pointers and non-struct values
+func MaskStruct(v any) map[string]any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return maskStruct(rv, 0)
+}
+
+func maskStruct(rv reflect.Value, depth int) map[string]any {
+	masked := make(map[string]any, rv.NumField())
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		masked[name] = maskField(field.Tag.Get("mask"), rv.Field(i), depth)
+	}
+	return masked
+}
+
+// maskField applies the mask tag to value or descends into nested structs
+func maskField(tag string, value reflect.Value, depth int) any {
+	switch tag {
+	case "redact":
+		return RedactionPlaceholder()
+	case "email":
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.String {
+			return "***"
+		}
+		return MaskEmail(value.String())
+	}
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct && !marshalsItself(value.Type()) {
+		if depth >= maskStructMaxDepth {
+			return nil
+		}
+		return maskStruct(value, depth+1)
+	}
+	return value.Interface()
+}
+
+// marshalsItself reports whether t controls its own encoding, so its fields should not be walked
+func marshalsItself(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textMarshalerType)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"time"
 	"unicode"
 
 	"github.com/go-playground/validator/v10"
@@ -42,6 +44,41 @@ func ValidatePassword(fl validator.FieldLevel) bool {
 	return hasLower && hasUpper && hasSpecial && hasNumber
 }
 
+// SupportedLocales lists the locales accepted in user profiles
+var SupportedLocales = map[string]bool{
+	"en":    true,
+	"en-US": true,
+	"en-GB": true,
+	"ru":    true,
+	"ru-RU": true,
+	"de":    true,
+	"de-DE": true,
+	"fr":    true,
+	"fr-FR": true,
+	"es":    true,
+	"es-ES": true,
+}
+
+// ValidateLocale checks that locale is one of SupportedLocales
+func ValidateLocale(locale string) error {
+	if !SupportedLocales[locale] {
+		return fmt.Errorf("unsupported locale: %s", locale)
+	}
+	return nil
+}
+
+// ValidateTimezone checks that timezone is an IANA zone name known to time.LoadLocation
+func ValidateTimezone(timezone string) error {
+	// "Local" resolves to the server zone and is meaningless for a user
+	if timezone == "" || timezone == "Local" {
+		return fmt.Errorf("unknown timezone: %s", timezone)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone: %s", timezone)
+	}
+	return nil
+}
+
 // RegisterCustomValidations registers custom validations
 func RegisterCustomValidations(v *validator.Validate) error {
 	return v.RegisterValidation("password", ValidatePassword)
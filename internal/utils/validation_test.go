@@ -84,3 +84,56 @@ func TestNewValidator(t *testing.T) {
 	err = validator.Var("pass", "password")
 	assert.Error(t, err)
 }
+
+func TestValidateLocale(t *testing.T) {
+	tests := []struct {
+		locale  string
+		isValid bool
+	}{
+		{locale: "en", isValid: true},
+		{locale: "en-US", isValid: true},
+		{locale: "ru-RU", isValid: true},
+		{locale: "", isValid: false},
+		{locale: "en_US", isValid: false},
+		{locale: "EN", isValid: false},
+		{locale: "xx-YY", isValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			err := ValidateLocale(tt.locale)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "unsupported locale: "+tt.locale)
+			}
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		isValid  bool
+	}{
+		{timezone: "UTC", isValid: true},
+		{timezone: "Europe/Berlin", isValid: true},
+		{timezone: "America/New_York", isValid: true},
+		{timezone: "", isValid: false},
+		{timezone: "Local", isValid: false},
+		{timezone: "Mars/Olympus_Mons", isValid: false},
+		{timezone: "europe/berlin", isValid: false},
+		{timezone: "../../etc/passwd", isValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			err := ValidateTimezone(tt.timezone)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "unknown timezone: "+tt.timezone)
+			}
+		})
+	}
+}
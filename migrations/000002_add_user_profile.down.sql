@@ -0,0 +1,5 @@
+-- Rollback user profile fields
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,5 @@
+-- Optional user profile fields set at registration
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN locale VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Rollback user role
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,3 @@
+-- User role, admin users are seeded by the bootstrap step
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'user';
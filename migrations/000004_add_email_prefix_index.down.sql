@@ -0,0 +1,2 @@
+-- Rollback email prefix search index
+DROP INDEX IF EXISTS idx_users_email_lower_prefix;
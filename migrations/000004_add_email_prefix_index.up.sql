@@ -0,0 +1,2 @@
+-- Case-insensitive email prefix search for admin autocomplete
+CREATE INDEX idx_users_email_lower_prefix ON users (LOWER(email) text_pattern_ops);
@@ -0,0 +1,2 @@
+-- Rollback role index
+DROP INDEX IF EXISTS idx_users_role;
@@ -0,0 +1,2 @@
+-- Listing users by role for authorization audits
+CREATE INDEX idx_users_role ON users (role) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback last login timestamp
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_login_at;
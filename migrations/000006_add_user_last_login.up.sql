@@ -0,0 +1,3 @@
+-- Last successful login, NULL until the user first logs in
+ALTER TABLE users
+    ADD COLUMN last_login_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Rollback session revocation timestamp
+ALTER TABLE users
+    DROP COLUMN IF EXISTS sessions_revoked_at;
@@ -0,0 +1,3 @@
+-- Tokens issued before this time, compared at second precision, are rejected, NULL when sessions were never revoked
+ALTER TABLE users
+    ADD COLUMN sessions_revoked_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Rollback failed login attempts counter
+ALTER TABLE users
+    DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,3 @@
+-- Consecutive failed login attempts, reset on successful login
+ALTER TABLE users
+    ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0;
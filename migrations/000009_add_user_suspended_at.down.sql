@@ -0,0 +1,3 @@
+-- Rollback account suspension timestamp
+ALTER TABLE users
+    DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,3 @@
+-- Logins are refused while set, NULL when the account is not suspended
+ALTER TABLE users
+    ADD COLUMN suspended_at TIMESTAMPTZ;